@@ -0,0 +1,46 @@
+package retry
+
+import "time"
+
+// Result is the outcome of a retry started by DoAsync
+type Result[T any] struct {
+	// Value returned by the successful attempt
+	Value T
+	// Err returned by the retry, nil on success
+	Err error
+	// Attempts is the count of executed attempts
+	Attempts uint
+	// Duration of the whole retry, including delays
+	Duration time.Duration
+}
+
+// DoAsync retries the function in a new goroutine and delivers the Result to the returned channel,
+// which is buffered (the goroutine never leaks when nobody receives) and closed after the Result,
+// so retries compose with select:
+//
+//	select {
+//	case res := <-retry.DoAsync(fetch, retry.Context(ctx)):
+//		...
+//	case <-time.After(time.Second):
+//		// use a cached value
+//	}
+func DoAsync[T any](retryableFunc RetryableFuncWithData[T], opts ...Option) <-chan Result[T] {
+	results := make(chan Result[T], 1)
+	go func() {
+		defer close(results)
+
+		var attempts uint
+		start := time.Now()
+		value, err := DoWithData(func() (T, error) {
+			attempts++
+			return retryableFunc()
+		}, opts...)
+		results <- Result[T]{
+			Value:    value,
+			Err:      err,
+			Attempts: attempts,
+			Duration: time.Since(start),
+		}
+	}()
+	return results
+}
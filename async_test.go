@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoAsync(t *testing.T) {
+	calls := 0
+	results := DoAsync(func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, errors.New("test")
+		}
+		return 42, nil
+	}, Delay(0))
+
+	var res Result[int]
+	select {
+	case res = <-results:
+	case <-time.After(time.Second):
+		t.Fatal("no result")
+	}
+	assert.NoError(t, res.Err)
+	assert.Equal(t, 42, res.Value)
+	assert.Equal(t, uint(3), res.Attempts)
+
+	_, ok := <-results
+	assert.False(t, ok, "closed after the result")
+
+	failed := <-DoAsync(func() (string, error) { return "", errors.New("test") }, Attempts(2), Delay(0))
+	assert.Error(t, failed.Err)
+	assert.Equal(t, uint(2), failed.Attempts)
+}
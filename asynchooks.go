@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// asyncHooksQueueSize is the capacity of the queue of hook calls with AsyncHooks
+const asyncHooksQueueSize = 1024
+
+// asyncHooks runs the hook calls of all retries with AsyncHooks one by one in a single goroutine
+var asyncHooks struct {
+	once    sync.Once
+	queue   chan func()
+	dropped uint64
+}
+
+// AsyncHooks runs OnRetry and OnDelay callbacks in a separate goroutine, so hooks doing network logging
+// or pushing metrics cannot add latency to the retry. The calls are queued in order in a bounded queue
+// shared by all retries; when the queue is full, the calls are dropped (see `DroppedAsyncHooks`)
+// instead of blocking the retry. Panics of the callbacks are recovered and ignored.
+//
+// The callbacks may run after the retry has returned, and concurrently with the retried function.
+func AsyncHooks(enabled bool) Option {
+	return func(c *Config) {
+		c.describe("AsyncHooks", enabled)
+		c.extend().asyncHooks = enabled
+	}
+}
+
+// DroppedAsyncHooks returns the count of OnRetry and OnDelay calls dropped because the queue of AsyncHooks was full
+func DroppedAsyncHooks() uint64 {
+	return atomic.LoadUint64(&asyncHooks.dropped)
+}
+
+// makeHooksAsync replaces OnRetry and OnDelay callbacks by ones queueing the calls
+func (c *Config) makeHooksAsync() {
+	onRetry, onDelay := c.onRetry, c.ext.onDelay
+	c.onRetry = func(n uint, err error) {
+		enqueueHook(func() { onRetry(n, err) })
+	}
+	c.extend().onDelay = func(n uint, d time.Duration) {
+		enqueueHook(func() { onDelay(n, d) })
+	}
+}
+
+func enqueueHook(call func()) {
+	asyncHooks.once.Do(func() {
+		asyncHooks.queue = make(chan func(), asyncHooksQueueSize)
+		go runAsyncHooks(asyncHooks.queue)
+	})
+
+	select {
+	case asyncHooks.queue <- call:
+	default:
+		atomic.AddUint64(&asyncHooks.dropped, 1)
+	}
+}
+
+func runAsyncHooks(queue <-chan func()) {
+	for call := range queue {
+		runAsyncHook(call)
+	}
+}
+
+func runAsyncHook(call func()) {
+	defer func() {
+		_ = recover()
+	}()
+	call()
+}
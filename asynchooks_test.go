@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAsyncHooks(t *testing.T) {
+	release := make(chan struct{})
+	calls := make(chan string, 10)
+	var count int
+	err := Do(
+		func() error {
+			count++
+			return errors.New("test")
+		},
+		Attempts(3),
+		Delay(0),
+		AsyncHooks(true),
+		OnRetry(func(n uint, err error) {
+			<-release // a slow hook does not block the retry
+			calls <- "retry"
+		}),
+		OnDelay(func(n uint, d time.Duration) {
+			calls <- "delay"
+		}),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 3, count)
+
+	close(release)
+	var received []string
+	for len(received) < 5 {
+		select {
+		case call := <-calls:
+			received = append(received, call)
+		case <-time.After(time.Second):
+			t.Fatalf("hooks not called, received %v", received)
+		}
+	}
+	assert.Equal(t, []string{"retry", "delay", "retry", "delay", "retry"}, received)
+}
+
+func TestAsyncHooksPanic(t *testing.T) {
+	called := make(chan struct{})
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(2),
+		Delay(0),
+		AsyncHooks(true),
+		OnRetry(func(n uint, err error) { panic("test") }),
+		OnDelay(func(n uint, d time.Duration) { close(called) }),
+	)
+	assert.Error(t, err)
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("hook after the panicking one not called")
+	}
+}
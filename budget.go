@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type budgetKey struct{}
+
+// Budget is what is left of the retry of the current attempt, see `BudgetFromContext`
+type Budget struct {
+	// Attempt is the number of the current attempt, starting from 1
+	Attempt uint
+	// RemainingAttempts is the count of attempts left after the current one (ignoring AttemptsForError),
+	// meaningless when Unlimited
+	RemainingAttempts uint
+	// Unlimited is set when the retry retries until success (Attempts(0))
+	Unlimited bool
+	// Deadline of the retry context, zero when there is none
+	Deadline time.Time
+}
+
+// Last reports whether the current attempt is the last one
+func (b Budget) Last() bool {
+	return !b.Unlimited && b.RemainingAttempts == 0
+}
+
+// budgetTracker holds the budget of the current attempt, it is updated by the retry loop before every attempt
+type budgetTracker struct {
+	mu     sync.Mutex
+	budget Budget
+}
+
+// BudgetFromContext returns the budget of the retry of the current attempt from the context
+// passed to the attempts by `DoContext` (false for other contexts).
+// Downstream layers which retry on their own can use it to avoid multiplicative retry amplification,
+// e.g. retry internally only on the last attempt of the outer retry:
+//
+//	func (c *Client) Get(ctx context.Context, key string) (string, error) {
+//		attempts := uint(3)
+//		if budget, ok := retry.BudgetFromContext(ctx); ok && !budget.Last() {
+//			attempts = 1 // the outer retry will retry
+//		}
+//		return retry.DoWithData(..., retry.Attempts(attempts), retry.Context(ctx))
+//	}
+func BudgetFromContext(ctx context.Context) (Budget, bool) {
+	b, ok := ctx.Value(budgetKey{}).(*budgetTracker)
+	if !ok {
+		return Budget{}, false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.budget, true
+}
+
+// update sets the budget of the current attempt of the config
+func (b *budgetTracker) update(c *Config) {
+	if b == nil {
+		return
+	}
+
+	budget := Budget{Attempt: c.attempted, Unlimited: c.attempts == 0}
+	if !budget.Unlimited && c.attempts > c.attempted {
+		budget.RemainingAttempts = c.attempts - c.attempted
+	}
+	budget.Deadline, _ = c.context.Deadline()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.budget = budget
+}
+
+func withBudgetTracker(b *budgetTracker) Option {
+	return func(c *Config) {
+		c.budget = b
+	}
+}
@@ -0,0 +1,44 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBudgetFromContext(t *testing.T) {
+	_, ok := BudgetFromContext(context.Background())
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	var budgets []Budget
+	err := DoContext(ctx, func(ctx context.Context) error {
+		budget, ok := BudgetFromContext(ctx)
+		assert.True(t, ok)
+		budgets = append(budgets, budget)
+		return errors.New("test")
+	}, Attempts(3), Delay(0))
+	assert.Error(t, err)
+
+	assert.Equal(t, []Budget{
+		{Attempt: 1, RemainingAttempts: 2, Deadline: deadline},
+		{Attempt: 2, RemainingAttempts: 1, Deadline: deadline},
+		{Attempt: 3, RemainingAttempts: 0, Deadline: deadline},
+	}, budgets)
+	assert.False(t, budgets[1].Last())
+	assert.True(t, budgets[2].Last())
+
+	err = DoContext(context.Background(), func(ctx context.Context) error {
+		budget, _ := BudgetFromContext(ctx)
+		assert.True(t, budget.Unlimited)
+		assert.False(t, budget.Last())
+		return nil
+	}, Attempts(0))
+	assert.NoError(t, err)
+}
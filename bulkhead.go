@@ -0,0 +1,69 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrBulkheadFull is returned without any attempt when the bulkhead of the retry is full, see `WithBulkhead`
+var ErrBulkheadFull = errors.New("retry: bulkhead full")
+
+// bulkheads are shared by all retries of the process, by key
+var bulkheads sync.Map // map[string]chan struct{}
+
+// WithBulkhead isolates retries by operation key (e.g. per dependency): at most maxConcurrent retries
+// with the key run at the same time (including their delays), further retries with the key fail immediately
+// with ErrBulkheadFull, so retries against one failing dependency can't consume all goroutines or connection slots
+// and starve the calls to healthy dependencies.
+//
+// Bulkheads are shared by the whole process and created by the first retry with the key,
+// a different maxConcurrent for an existing bulkhead is an invalid option.
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.WithBulkhead("payments", 20),
+//	)
+func WithBulkhead(key string, maxConcurrent int) Option {
+	return func(c *Config) {
+		c.describe("WithBulkhead", key, maxConcurrent)
+		if maxConcurrent < 1 {
+			c.invalid("WithBulkhead maxConcurrent must be positive, got %d", maxConcurrent)
+			return
+		}
+		if slots, ok := bulkheads.Load(key); ok && cap(slots.(chan struct{})) != maxConcurrent {
+			c.invalid("WithBulkhead %q has maxConcurrent %d, got %d", key, cap(slots.(chan struct{})), maxConcurrent)
+			return
+		}
+		ext := c.extend()
+		ext.bulkheadKey = key
+		ext.bulkheadLimit = maxConcurrent
+	}
+}
+
+// enterBulkhead takes a slot of the bulkhead (if any), the bulkhead is created on first use.
+// It returns the slots to release with leaveBulkhead and false when the bulkhead is full.
+func (c *Config) enterBulkhead() (chan struct{}, bool) {
+	if c.ext.bulkheadLimit == 0 {
+		return nil, true
+	}
+	v, ok := bulkheads.Load(c.ext.bulkheadKey)
+	if !ok {
+		v, _ = bulkheads.LoadOrStore(c.ext.bulkheadKey, make(chan struct{}, c.ext.bulkheadLimit))
+	}
+	slots := v.(chan struct{})
+	select {
+	case slots <- struct{}{}:
+		return slots, true
+	default:
+		return nil, false
+	}
+}
+
+// leaveBulkhead releases the slot taken by enterBulkhead
+func leaveBulkhead(slots chan struct{}) {
+	if slots != nil {
+		<-slots
+	}
+}
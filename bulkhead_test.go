@@ -0,0 +1,46 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBulkhead(t *testing.T) {
+	errTest := errors.New("test")
+	inner := errors.New("inner")
+
+	err := Do(func() error {
+		// the retry of the same key is rejected while the outer one occupies the only slot
+		innerErr := Do(func() error { return inner }, WithBulkhead("TestWithBulkhead", 1))
+		assert.ErrorIs(t, innerErr, ErrBulkheadFull)
+
+		// other keys are isolated
+		assert.NoError(t, Do(func() error { return nil }, WithBulkhead("TestWithBulkhead-other", 1)))
+		return errTest
+	}, Attempts(2), Delay(0), WithBulkhead("TestWithBulkhead", 1))
+	assert.ErrorIs(t, err, errTest)
+
+	// the slot is released
+	assert.NoError(t, Do(func() error { return nil }, WithBulkhead("TestWithBulkhead", 1)))
+
+	assert.ErrorIs(t, Validate(WithBulkhead("TestWithBulkhead", 0)), ErrInvalidOption)
+
+	// a different limit for the existing bulkhead is invalid
+	assert.ErrorIs(t, Validate(WithBulkhead("TestWithBulkhead", 2)), ErrInvalidOption)
+	assert.ErrorIs(t, Do(func() error { return nil }, WithBulkhead("TestWithBulkhead", 2)), ErrInvalidOption)
+}
+
+func TestWithBulkheadLazy(t *testing.T) {
+	assert.NoError(t, Validate(WithBulkhead("TestWithBulkheadLazy", 1)))
+	_, ok := bulkheads.Load("TestWithBulkheadLazy")
+	assert.False(t, ok, "the bulkhead is created by the first retry")
+
+	// not created yet, so any limit is valid
+	assert.NoError(t, Do(func() error { return nil }, WithBulkhead("TestWithBulkheadLazy", 3)))
+	slots, ok := bulkheads.Load("TestWithBulkheadLazy")
+	if assert.True(t, ok) {
+		assert.Equal(t, 3, cap(slots.(chan struct{})))
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !go1.20
+
+package retry
+
+import "context"
+
+// contextCause returns ctx.Err(), causes of cancellation are supported since go1.20
+func contextCause(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// withCancelCause is context.WithCancel, causes of cancellation are supported since go1.20
+func withCancelCause(parent context.Context) (context.Context, func(cause error)) {
+	ctx, cancel := context.WithCancel(parent)
+	return ctx, func(error) { cancel() }
+}
@@ -0,0 +1,17 @@
+//go:build go1.20
+
+package retry
+
+import "context"
+
+// contextCause returns the cause of the cancellation of ctx (see context.WithCancelCause),
+// or ctx.Err() when no cause was given
+func contextCause(ctx context.Context) error {
+	return context.Cause(ctx)
+}
+
+// withCancelCause is context.WithCancelCause
+func withCancelCause(parent context.Context) (context.Context, func(cause error)) {
+	ctx, cancel := context.WithCancelCause(parent)
+	return ctx, context.CancelCauseFunc(cancel)
+}
@@ -0,0 +1,50 @@
+//go:build go1.20
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContextCause(t *testing.T) {
+	errShutdown := errors.New("shutting down")
+
+	t.Run("cancelled before", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(errShutdown)
+
+		err := Do(func() error { return nil }, Context(ctx))
+		assert.Equal(t, errShutdown, err)
+	})
+
+	t.Run("cancelled while retrying", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		err := Do(func() error {
+			cancel(errShutdown)
+			return errors.New("test")
+		}, Context(ctx), LastErrorOnly(true))
+		assert.Equal(t, errShutdown, err)
+	})
+
+	t.Run("infinite retry wrapped with last error", func(t *testing.T) {
+		ctx, cancel := context.WithCancelCause(context.Background())
+		err := Do(func() error {
+			cancel(errShutdown)
+			return errors.New("test")
+		}, Context(ctx), Attempts(0), WrapContextErrorWithLastError(true))
+		assert.ErrorIs(t, err, errShutdown)
+		assert.EqualError(t, err, "All attempts fail:\n#1: shutting down\n#2: test")
+	})
+
+	t.Run("without cause", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err := Do(func() error { return nil }, Context(ctx))
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
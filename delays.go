@@ -0,0 +1,89 @@
+package retry
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// maxCachedDelaySchedules limits count of delay schedules kept by precomputeDelays
+const maxCachedDelaySchedules = 256
+
+type delayScheduleKey struct {
+	backOff bool
+	delay   time.Duration
+}
+
+// delaySchedules are the schedules of deterministic delay types shared by all retries with the same delay
+var delaySchedules = struct {
+	sync.RWMutex
+	m map[delayScheduleKey][]time.Duration
+}{m: make(map[delayScheduleKey][]time.Duration)}
+
+var (
+	fixedDelayPointer       = reflect.ValueOf(FixedDelay).Pointer()
+	backOffDelayPointer     = reflect.ValueOf(BackOffDelay).Pointer()
+	backOffDelayPurePointer = reflect.ValueOf(BackOffDelayPure).Pointer()
+)
+
+// precomputeDelays sets the schedule of the delay type when it is deterministic
+// (FixedDelay, or BackOffDelay and BackOffDelayPure without RandomizationFactor), so delay indexes into it
+// instead of computing every delay. The schedule ends with the delay repeated for all further attempts.
+func (c *Config) precomputeDelays() {
+	if c.delayType == nil || c.ext.err != nil {
+		return
+	}
+
+	var key delayScheduleKey
+	switch reflect.ValueOf(c.delayType).Pointer() {
+	case fixedDelayPointer:
+		key = delayScheduleKey{delay: c.delay}
+	case backOffDelayPointer, backOffDelayPurePointer:
+		if c.ext.randomizationFactor > 0 {
+			return
+		}
+		key = delayScheduleKey{backOff: true, delay: c.delay}
+	default:
+		return
+	}
+
+	delaySchedules.RLock()
+	schedule, ok := delaySchedules.m[key]
+	delaySchedules.RUnlock()
+	if ok {
+		c.delaySchedule = schedule
+		return
+	}
+
+	if key.backOff {
+		// the back-off stops growing after maxBackOffN
+		delay := key.delay
+		if delay <= 0 {
+			delay = 1
+		}
+		schedule = make([]time.Duration, maxBackOffN(delay)+1)
+		for n := range schedule {
+			schedule[n] = backOff(delay, uint(n))
+		}
+	} else {
+		schedule = []time.Duration{key.delay}
+	}
+	c.delaySchedule = schedule
+
+	delaySchedules.Lock()
+	if len(delaySchedules.m) < maxCachedDelaySchedules {
+		delaySchedules.m[key] = schedule
+	}
+	delaySchedules.Unlock()
+}
+
+// scheduledDelay returns the n-th delay of the precomputed schedule, false without one
+func (c *Config) scheduledDelay(n uint) (time.Duration, bool) {
+	if c.delaySchedule == nil {
+		return 0, false
+	}
+	if last := uint(len(c.delaySchedule) - 1); n > last {
+		n = last
+	}
+	return c.delaySchedule[n], true
+}
@@ -0,0 +1,33 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrecomputeDelays(t *testing.T) {
+	for _, opts := range [][]Option{
+		{DelayType(FixedDelay), Delay(time.Second)},
+		{DelayType(BackOffDelay), Delay(time.Millisecond)},
+		{DelayType(BackOffDelay), Delay(0)},
+		{DelayType(BackOffDelay), Delay(time.Hour), MaxDelay(24 * time.Hour)},
+		{DelayType(BackOffDelayPure), Delay(time.Second)},
+	} {
+		config := newRetryConfig(opts)
+		assert.NotNil(t, config.delaySchedule)
+
+		computed := newDefaultRetryConfig()
+		for _, opt := range opts {
+			opt(computed)
+		}
+		for _, n := range []uint{0, 1, 2, 10, 40, 62, 63, 100} {
+			assert.Equal(t, delay(computed, n, nil), delay(config, n, nil), "n=%d", n)
+		}
+	}
+
+	assert.Nil(t, newRetryConfig(nil).delaySchedule, "random delays are not precomputed")
+	assert.Nil(t, newRetryConfig([]Option{DelayType(BackOffDelay), RandomizationFactor(0.5)}).delaySchedule)
+
+}
@@ -0,0 +1,91 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// OptionInfo describes an Option by its name and parameters.
+// Functions are described by their names, contexts and timers by their kinds,
+// so OptionInfo can be printed, compared and serialized.
+type OptionInfo struct {
+	Name   string `json:"name"`
+	Params []any  `json:"params,omitempty"`
+}
+
+// String returns the option as it would be written in code, e.g. `Attempts(3)`
+func (o OptionInfo) String() string {
+	params := make([]string, len(o.Params))
+	for i, p := range o.Params {
+		params[i] = fmt.Sprint(p)
+	}
+	return fmt.Sprintf("%s(%s)", o.Name, strings.Join(params, ", "))
+}
+
+// Describe returns descriptions of given options in order, so policies can be logged, compared and diffed.
+// Options not coming from this package (or nil options) are described as `Option`.
+//
+//	fmt.Println(retry.Describe(retry.Attempts(3), retry.Delay(time.Second)))
+//	// [Attempts(3) Delay(1s)]
+func Describe(opts ...Option) []OptionInfo {
+	var infos []OptionInfo
+	config := newDefaultRetryConfig()
+	config.extend().infos = &infos
+
+	for _, opt := range opts {
+		n := len(infos)
+		if opt != nil {
+			opt(config)
+		}
+		if len(infos) == n {
+			infos = append(infos, OptionInfo{Name: "Option"})
+		}
+	}
+
+	return infos
+}
+
+// describe records the option applied to c when the options are described by Describe.
+// The params are described (and copied) only then, so applying options doesn't pay for their descriptions.
+func (c *Config) describe(name string, params ...any) {
+	if c.ext.infos == nil {
+		return
+	}
+	var described []any
+	for _, p := range params {
+		described = append(described, describeParam(p))
+	}
+	*c.ext.infos = append(*c.ext.infos, OptionInfo{Name: name, Params: described})
+}
+
+// describeParam describes functions by their names, contexts and signals as printed
+// and timers, clocks, semaphores, collectors and random sources by their types
+func describeParam(p any) any {
+	switch p := p.(type) {
+	case context.Context, []os.Signal:
+		return fmt.Sprint(p)
+	case Timer, Semaphore, StatsCollector, rand.Source:
+		return fmt.Sprintf("%T", p)
+	}
+	if reflect.ValueOf(p).Kind() == reflect.Func {
+		return funcName(p)
+	}
+	return p
+}
+
+// funcName returns the name of the function, e.g. `github.com/avast/retry-go/v4.BackOffDelay`
+func funcName(f any) string {
+	v := reflect.ValueOf(f)
+	if v.Kind() != reflect.Func || v.IsNil() {
+		return fmt.Sprint(f)
+	}
+	if fn := runtime.FuncForPC(v.Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return fmt.Sprintf("%T", f)
+}
@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescribe(t *testing.T) {
+	errTest := errors.New("test")
+	infos := Describe(
+		Attempts(3),
+		Delay(time.Second),
+		AttemptsForError(2, errTest),
+		DelayType(FixedDelay),
+		func(c *Config) {},
+	)
+
+	assert.Equal(t, []OptionInfo{
+		{Name: "Attempts", Params: []any{uint(3)}},
+		{Name: "Delay", Params: []any{time.Second}},
+		{Name: "AttemptsForError", Params: []any{uint(2), errTest}},
+		{Name: "DelayType", Params: []any{"github.com/avast/retry-go/v4.FixedDelay"}},
+		{Name: "Option"},
+	}, infos)
+
+	assert.Equal(t, "[Attempts(3) Delay(1s) AttemptsForError(2, test) DelayType(github.com/avast/retry-go/v4.FixedDelay) Option()]", fmt.Sprint(infos))
+}
+
+func TestDescribeJSON(t *testing.T) {
+	b, err := json.Marshal(Describe(Attempts(3), LastErrorOnly(true), WithRecorder(&Recorder{})))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"name":"Attempts","params":[3]},{"name":"LastErrorOnly","params":[true]},{"name":"WithRecorder"}]`, string(b))
+}
+
+func TestDescribeDoesNotChangeBehaviour(t *testing.T) {
+	assert.Empty(t, Describe())
+
+	err := Do(func() error { return errors.New("test") }, Attempts(2), Delay(0), DelayType(FixedDelay))
+	assert.Len(t, err.(Error), 2)
+}
+
+func TestDescribeParams(t *testing.T) {
+	infos := Describe(
+		Context(context.Background()),
+		WithTimer(&recordingTimer{}),
+		OnRetry(func(n uint, err error) {}),
+	)
+	assert.Equal(t, "[Context(context.Background) WithTimer(*retry.recordingTimer) OnRetry(github.com/avast/retry-go/v4.TestDescribeParams.func1)]", fmt.Sprint(infos))
+}
+
+func TestDescribeAllocs(t *testing.T) {
+	config := newDefaultRetryConfig()
+	opts := []Option{Delay(time.Hour), DelayType(FixedDelay), Context(context.Background())}
+	allocs := testing.AllocsPerRun(100, func() {
+		for _, opt := range opts {
+			opt(config)
+		}
+	})
+	assert.Zero(t, allocs, "the options are described only by Describe")
+}
@@ -0,0 +1,63 @@
+package retry
+
+import "sync"
+
+// Each retries fn for every item independently under the same options
+// and returns the errors of the items which failed, keyed by their index (empty when all succeeded).
+// Items are processed one by one unless Concurrency is set.
+//
+//	failed := retry.Each(events, func(e Event) error {
+//		return publish(e)
+//	}, retry.Attempts(3), retry.Concurrency(4))
+//	for i, err := range failed {
+//		log.Printf("event %d not sent: %s", events[i].ID, err)
+//	}
+func Each[T any](items []T, fn func(T) error, opts ...Option) map[int]error {
+	concurrency := newRetryConfig(opts).ext.concurrency
+	if concurrency == 0 {
+		concurrency = 1
+	}
+
+	var mu sync.Mutex
+	failed := make(map[int]error)
+	retryItem := func(i int) {
+		if err := Do(func() error { return fn(items[i]) }, opts...); err != nil {
+			mu.Lock()
+			failed[i] = err
+			mu.Unlock()
+		}
+	}
+
+	if concurrency == 1 {
+		for i := range items {
+			retryItem(i)
+		}
+		return failed
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for i := range items {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			retryItem(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return failed
+}
+
+// Concurrency sets how many items are retried concurrently by `Each`, it has no effect on `retry.Do`.
+// default is 1
+func Concurrency(concurrency uint) Option {
+	return func(c *Config) {
+		c.describe("Concurrency", concurrency)
+		c.extend().concurrency = concurrency
+	}
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEach(t *testing.T) {
+	calls := map[string]int{}
+	failed := Each([]string{"a", "b", "c"}, func(item string) error {
+		calls[item]++
+		if item == "b" || (item == "c" && calls[item] < 2) {
+			return errors.New(item)
+		}
+		return nil
+	}, Attempts(3), Delay(0))
+
+	assert.Equal(t, map[string]int{"a": 1, "b": 3, "c": 2}, calls)
+	assert.Len(t, failed, 1)
+	assert.Len(t, failed[1].(Error), 3)
+}
+
+func TestEachConcurrency(t *testing.T) {
+	var running, maxRunning int32
+	var mu sync.Mutex
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+
+	failed := Each(items, func(item int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		mu.Lock()
+		if n > maxRunning {
+			maxRunning = n
+		}
+		mu.Unlock()
+
+		if item%2 == 0 {
+			return errors.New("even")
+		}
+		return nil
+	}, Attempts(2), Delay(0), Concurrency(3))
+
+	assert.Len(t, failed, 10)
+	assert.Contains(t, failed, 4)
+	assert.LessOrEqual(t, maxRunning, int32(3))
+}
@@ -0,0 +1,48 @@
+package retry
+
+import "errors"
+
+// IsConnectionRefused checks if error is (or wraps) a "connection refused" OS error,
+// e.g. buried inside `*net.OpError` or `*os.SyscallError`
+func IsConnectionRefused(err error) bool {
+	return isAnyOf(err, connectionRefusedErrors)
+}
+
+// IsConnectionReset checks if error is (or wraps) a "connection reset" or "connection aborted" OS error
+func IsConnectionReset(err error) bool {
+	return isAnyOf(err, connectionResetErrors)
+}
+
+// IsTimedOut checks if error is (or wraps) an OS level "timed out" error
+func IsTimedOut(err error) bool {
+	return isAnyOf(err, timedOutErrors)
+}
+
+// IsBrokenPipe checks if error is (or wraps) a "broken pipe" OS error
+func IsBrokenPipe(err error) bool {
+	return isAnyOf(err, brokenPipeErrors)
+}
+
+// IsTransientOSError checks if error is one of the transient OS errors above.
+// It can be used directly as RetryIfFunc to retry only genuinely transient OS errors:
+//
+//	retry.Do(
+//		func() error {
+//			conn, err := net.Dial("tcp", addr)
+//			...
+//		},
+//		retry.RetryIf(retry.IsTransientOSError),
+//	)
+func IsTransientOSError(err error) bool {
+	return IsRecoverable(err) &&
+		(IsConnectionRefused(err) || IsConnectionReset(err) || IsTimedOut(err) || IsBrokenPipe(err))
+}
+
+func isAnyOf(err error, targets []error) bool {
+	for _, target := range targets {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,11 @@
+//go:build plan9
+
+package retry
+
+// Plan 9 reports OS errors as strings, there are no errno values to match
+var (
+	connectionRefusedErrors []error
+	connectionResetErrors   []error
+	timedOutErrors          []error
+	brokenPipeErrors        []error
+)
@@ -0,0 +1,54 @@
+//go:build !plan9
+
+package retry
+
+import (
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransientOSErrors(t *testing.T) {
+	opErr := func(err error) error {
+		return &net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", err)}
+	}
+
+	refused := opErr(syscall.ECONNREFUSED)
+	assert.True(t, IsConnectionRefused(refused))
+	assert.False(t, IsConnectionReset(refused))
+	assert.True(t, IsTransientOSError(refused))
+
+	reset := opErr(syscall.ECONNRESET)
+	assert.True(t, IsConnectionReset(reset))
+	assert.True(t, IsTransientOSError(reset))
+
+	timedOut := opErr(syscall.ETIMEDOUT)
+	assert.True(t, IsTimedOut(timedOut))
+	assert.True(t, IsTransientOSError(timedOut))
+
+	brokenPipe := &os.PathError{Op: "write", Path: "pipe", Err: syscall.EPIPE}
+	assert.True(t, IsBrokenPipe(brokenPipe))
+	assert.True(t, IsTransientOSError(brokenPipe))
+
+	assert.False(t, IsTransientOSError(opErr(syscall.EACCES)))
+	assert.False(t, IsTransientOSError(errors.New("connection refused")))
+	assert.False(t, IsTransientOSError(Unrecoverable(refused)))
+}
+
+func TestTransientOSErrorDial(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("unable to listen:", err)
+	}
+	addr := l.Addr().String()
+	l.Close()
+
+	_, err = net.Dial("tcp", addr)
+	assert.Error(t, err)
+	assert.True(t, IsConnectionRefused(err))
+	assert.True(t, IsTransientOSError(err))
+}
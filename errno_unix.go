@@ -0,0 +1,12 @@
+//go:build !windows && !plan9
+
+package retry
+
+import "syscall"
+
+var (
+	connectionRefusedErrors = []error{syscall.ECONNREFUSED}
+	connectionResetErrors   = []error{syscall.ECONNRESET, syscall.ECONNABORTED}
+	timedOutErrors          = []error{syscall.ETIMEDOUT}
+	brokenPipeErrors        = []error{syscall.EPIPE}
+)
@@ -0,0 +1,21 @@
+//go:build windows
+
+package retry
+
+import "syscall"
+
+// Windows error codes not defined in package syscall
+const (
+	errnoNoData          syscall.Errno = 232 // ERROR_NO_DATA, the pipe is being closed
+	errnoWSAECONNABORTED syscall.Errno = 10053
+	errnoWSAECONNRESET   syscall.Errno = 10054
+	errnoWSAETIMEDOUT    syscall.Errno = 10060
+	errnoWSAECONNREFUSED syscall.Errno = 10061
+)
+
+var (
+	connectionRefusedErrors = []error{syscall.ECONNREFUSED, errnoWSAECONNREFUSED}
+	connectionResetErrors   = []error{syscall.ECONNRESET, syscall.ECONNABORTED, errnoWSAECONNRESET, errnoWSAECONNABORTED}
+	timedOutErrors          = []error{syscall.ETIMEDOUT, errnoWSAETIMEDOUT}
+	brokenPipeErrors        = []error{syscall.EPIPE, syscall.ERROR_BROKEN_PIPE, errnoNoData}
+)
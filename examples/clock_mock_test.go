@@ -0,0 +1,65 @@
+package retry_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/benbjohnson/clock"
+	"github.com/stretchr/testify/assert"
+)
+
+// clock.Clock and *clock.Mock of github.com/benbjohnson/clock satisfy retry.Clock
+var (
+	_ retry.Clock = clock.New()
+	_ retry.Clock = clock.NewMock()
+)
+
+// waitingClock is the mock clock reporting every delay the retry starts to wait for,
+// so the test advances the virtual time only once the timer of the retry is set
+type waitingClock struct {
+	*clock.Mock
+	waits chan time.Duration
+}
+
+func (c waitingClock) After(d time.Duration) <-chan time.Time {
+	ch := c.Mock.After(d)
+	c.waits <- d
+	return ch
+}
+
+// TestMockClock shows how to drive retries by virtual time of a mock clock
+func TestMockClock(t *testing.T) {
+	mock := clock.NewMock()
+	start := mock.Now()
+	waiting := waitingClock{Mock: mock, waits: make(chan time.Duration)}
+
+	attempts := 0
+	done := make(chan error)
+	go func() {
+		done <- retry.Do(
+			func() error {
+				attempts++
+				return errors.New("test")
+			},
+			retry.Attempts(3),
+			retry.Delay(time.Hour),
+			retry.DelayType(retry.FixedDelay),
+			retry.WithClock(waiting),
+		)
+	}()
+
+	// the retry waits twice, between its 3 attempts
+	for i := 0; i < 2; i++ {
+		d := <-waiting.waits
+		assert.Equal(t, time.Hour, d)
+		// advances virtual time by the delay, fires the timer of the retry
+		mock.Add(d)
+	}
+
+	err := <-done
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 2*time.Hour, mock.Now().Sub(start))
+}
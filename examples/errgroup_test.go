@@ -0,0 +1,36 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/errgroup"
+)
+
+// *errgroup.Group satisfies retry.Group
+var _ retry.Group = &errgroup.Group{}
+
+// TestGroupGo shows that a failed task of an errgroup stops the retries of the other tasks
+func TestGroupGo(t *testing.T) {
+	g, ctx := errgroup.WithContext(context.Background())
+	errFatal := errors.New("fatal")
+
+	var attempts int32
+	retry.GroupGo(ctx, g, func(ctx context.Context) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("flaky")
+	}, retry.Attempts(0), retry.Delay(10*time.Millisecond), retry.DelayType(retry.FixedDelay))
+
+	retry.GroupGo(ctx, g, func(ctx context.Context) error {
+		return retry.Unrecoverable(errFatal)
+	})
+
+	err := g.Wait()
+	assert.ErrorIs(t, err, errFatal)
+	assert.Less(t, atomic.LoadInt32(&attempts), int32(10))
+}
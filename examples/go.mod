@@ -0,0 +1,18 @@
+module github.com/avast/retry-go/v4/examples
+
+go 1.18
+
+require (
+	github.com/avast/retry-go/v4 v4.5.0
+	github.com/benbjohnson/clock v1.3.5
+	github.com/stretchr/testify v1.8.4
+	golang.org/x/sync v0.3.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/avast/retry-go/v4 => ../
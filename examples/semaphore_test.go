@@ -0,0 +1,9 @@
+package retry_test
+
+import (
+	"github.com/avast/retry-go/v4"
+	"golang.org/x/sync/semaphore"
+)
+
+// *semaphore.Weighted satisfies retry.Semaphore
+var _ retry.Semaphore = semaphore.NewWeighted(10)
@@ -0,0 +1,50 @@
+package retry_test
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/singleflight"
+)
+
+// *singleflight.Group satisfies retry.Singleflight
+var _ retry.Singleflight = &singleflight.Group{}
+
+// TestSingleflight shows concurrent callers sharing one retry loop
+func TestSingleflight(t *testing.T) {
+	var group singleflight.Group
+	var attempts int32
+	release := make(chan struct{})
+
+	fetch := func() (string, error) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return "", errors.New("down")
+		}
+		<-release
+		return "user", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			user, err := retry.DoWithData(fetch, retry.Delay(time.Millisecond), retry.WithSingleflight("user:1", &group))
+			assert.NoError(t, err)
+			results[i] = user
+		}(i)
+	}
+
+	time.Sleep(50 * time.Millisecond) // let all callers join the flight
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, []string{"user", "user", "user", "user", "user"}, results)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+}
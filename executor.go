@@ -0,0 +1,110 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrExecutorClosed is returned by `Executor.Submit` after the executor was closed
+var ErrExecutorClosed = errors.New("retry: executor closed")
+
+// Executor retries any number of submitted jobs concurrently, but bounds the count of attempts
+// running at the same time across all of them. Jobs waiting for their backoff delay don't hold a slot,
+// so a mass backfill doesn't hammer a dependency with thousands of simultaneous attempts
+// and slow jobs don't starve the others while backing off (unlike a Scheduler, whose workers hold
+// a slot for the whole retry of a job).
+//
+//	e := retry.NewExecutor(16, retry.Attempts(5))
+//	for _, item := range items {
+//		item := item
+//		if _, err := e.Submit(func() error { return backfill(item) }); err != nil {
+//			...
+//		}
+//	}
+//	e.Close()
+type Executor struct {
+	opts []Option
+	sem  chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+
+	wg sync.WaitGroup
+}
+
+// NewExecutor returns an Executor running at most given count of attempts at the same time (at least one).
+// Options are applied to every job before the options of the job itself.
+func NewExecutor(concurrency int, opts ...Option) *Executor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Executor{
+		opts: opts,
+		sem:  make(chan struct{}, concurrency),
+	}
+}
+
+// Submit starts retrying the function in the background.
+// The job is pending while its attempt waits for a free slot and running while it is retried.
+// When the context of the job (see `Context`) is done while waiting for a slot, the attempt fails with the context error.
+func (e *Executor) Submit(retryableFunc RetryableFunc, opts ...Option) (*Job, error) {
+	jobOpts := make([]Option, 0, len(e.opts)+len(opts))
+	jobOpts = append(jobOpts, e.opts...)
+	jobOpts = append(jobOpts, opts...)
+
+	job := &Job{
+		retryableFunc: retryableFunc,
+		opts:          jobOpts,
+		done:          make(chan struct{}),
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return nil, ErrExecutorClosed
+	}
+	e.wg.Add(1)
+	go e.run(job)
+
+	return job, nil
+}
+
+// InFlight returns count of attempts running right now
+func (e *Executor) InFlight() int {
+	return len(e.sem)
+}
+
+// Close stops accepting new jobs and waits until all submitted jobs are finished.
+// Use `retry.Context` to cancel long running jobs.
+func (e *Executor) Close() {
+	e.mu.Lock()
+	e.closed = true
+	e.mu.Unlock()
+
+	e.wg.Wait()
+}
+
+func (e *Executor) run(job *Job) {
+	defer e.wg.Done()
+
+	config := newRetryConfig(job.opts)
+	ctx := config.context
+	_, err := do[struct{}](config, callerFunc(func() error {
+		select {
+		case e.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		defer func() { <-e.sem }()
+
+		job.setStatus(JobRunning, nil)
+		return job.retryableFunc()
+	}))
+
+	if err != nil {
+		job.setStatus(JobFailed, err)
+	} else {
+		job.setStatus(JobSucceeded, nil)
+	}
+	close(job.done)
+}
@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor(t *testing.T) {
+	e := NewExecutor(2, Attempts(3), Delay(time.Millisecond), DelayType(FixedDelay))
+
+	var running, maxRunning int32
+	var mu sync.Mutex
+	var jobs []*Job
+	for i := 0; i < 10; i++ {
+		i := i
+		calls := 0
+		job, err := e.Submit(func() error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			mu.Lock()
+			if n > maxRunning {
+				maxRunning = n
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+			calls++
+			if i == 0 || calls < 2 {
+				return errors.New("test")
+			}
+			return nil
+		})
+		assert.NoError(t, err)
+		jobs = append(jobs, job)
+	}
+	e.Close()
+
+	assert.LessOrEqual(t, maxRunning, int32(2))
+	assert.Equal(t, 0, e.InFlight())
+	assert.Equal(t, JobFailed, jobs[0].Status())
+	assert.Len(t, jobs[0].Err().(Error), 3)
+	for _, job := range jobs[1:] {
+		assert.Equal(t, JobSucceeded, job.Status())
+	}
+
+	_, err := e.Submit(func() error { return nil })
+	assert.ErrorIs(t, err, ErrExecutorClosed)
+}
+
+func TestExecutorAppliesOptionsOnce(t *testing.T) {
+	var applied int32
+	e := NewExecutor(1, Option(func(c *Config) { atomic.AddInt32(&applied, 1) }))
+	job, err := e.Submit(func() error { return nil })
+	assert.NoError(t, err)
+	assert.NoError(t, job.Wait())
+	e.Close()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&applied))
+}
@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"expvar"
+	"sync/atomic"
+)
+
+// names of the counters published by PublishExpvar
+const (
+	expvarAttempts  = "attempts"
+	expvarActive    = "active"
+	expvarExhausted = "exhausted"
+)
+
+// expvars are the published counters, nil until PublishExpvar is called
+var expvars atomic.Value // *expvar.Map
+
+// PublishExpvar publishes package-wide retry counters as an expvar map with the given name
+// (e.g. "retry", served by expvar at /debug/vars):
+//
+//   - attempts: total count of executed attempts
+//   - active: count of currently running retries
+//   - exhausted: count of retries which failed because they ran out of attempts
+//
+// Counting is disabled until PublishExpvar is called. Like expvar.Publish,
+// it panics when the name is already published, so call it once, e.g. from main.
+//
+//	func main() {
+//		retry.PublishExpvar("retry")
+//		...
+//	}
+func PublishExpvar(name string) {
+	m := expvar.NewMap(name)
+	m.Add(expvarAttempts, 0)
+	m.Add(expvarActive, 0)
+	m.Add(expvarExhausted, 0)
+	expvars.Store(m)
+}
+
+type expvarMap struct {
+	m *expvar.Map
+}
+
+func loadExpvars() expvarMap {
+	m, _ := expvars.Load().(*expvar.Map)
+	return expvarMap{m: m}
+}
+
+// add adds delta to the counter, it does nothing when expvar is not published
+func (v expvarMap) add(key string, delta int64) {
+	if v.m != nil && delta != 0 {
+		v.m.Add(key, delta)
+	}
+}
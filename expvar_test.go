@@ -0,0 +1,35 @@
+package retry
+
+import (
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishExpvar(t *testing.T) {
+	if expvar.Get("retry_test") == nil { // -count > 1
+		PublishExpvar("retry_test")
+	}
+	m := expvar.Get("retry_test").(*expvar.Map)
+	counter := func(key string) int64 {
+		return m.Get(key).(*expvar.Int).Value()
+	}
+	attempts, exhausted := counter("attempts"), counter("exhausted")
+
+	assert.Equal(t, int64(0), counter("active"))
+
+	err := Do(func() error {
+		assert.Equal(t, int64(1), counter("active"))
+		return errors.New("test")
+	}, Attempts(3), Delay(0))
+	assert.Error(t, err)
+
+	err = Do(func() error { return Unrecoverable(errors.New("test")) })
+	assert.Error(t, err)
+
+	assert.Equal(t, attempts+4, counter("attempts"))
+	assert.Equal(t, int64(0), counter("active"))
+	assert.Equal(t, exhausted+1, counter("exhausted"))
+}
@@ -0,0 +1,39 @@
+package retry
+
+import "errors"
+
+// ErrInjectedFault is the error of attempts failed by WithFaultInjection when no error is given
+var ErrInjectedFault = errors.New("retry: injected fault")
+
+// WithFaultInjection fails the given ratio of successful attempts with the error (ErrInjectedFault if nil),
+// so it can be verified e.g. in staging that the retry (and alerting) configuration behaves under failure.
+// The injected failures are handled as if they were returned by the retried function.
+//
+//	opts := []retry.Option{retry.Attempts(5)}
+//	if os.Getenv("CHAOS") != "" {
+//		opts = append(opts, retry.WithFaultInjection(0.3, nil))
+//	}
+//
+// rate must be between 0 and 1, default is 0 (no faults)
+func WithFaultInjection(rate float64, err error) Option {
+	return func(c *Config) {
+		c.describe("WithFaultInjection", rate, err)
+		if rate < 0 || rate > 1 {
+			c.invalid("WithFaultInjection rate must be between 0 and 1, got %v", rate)
+			return
+		}
+		if err == nil {
+			err = ErrInjectedFault
+		}
+		c.extend().faultRate = rate
+		c.extend().faultErr = err
+	}
+}
+
+// injectFault returns the injected error instead of the successful result of an attempt, nil when there is no fault
+func (c *Config) injectFault() error {
+	if c.ext.faultRate > 0 && c.random().Float64() < c.ext.faultRate {
+		return c.ext.faultErr
+	}
+	return nil
+}
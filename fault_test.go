@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFaultInjection(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return nil
+	}, Attempts(3), Delay(0), WithFaultInjection(1, nil))
+	assert.ErrorIs(t, err, ErrInjectedFault)
+	assert.Equal(t, 3, calls, "injected faults are retried")
+
+	chaos := errors.New("chaos")
+	calls = 0
+	_, err = DoWithData(func() (int, error) {
+		calls++
+		return 42, nil
+	}, Attempts(1), WithFaultInjection(1, chaos))
+	assert.Equal(t, Error{chaos}, err)
+
+	calls = 0
+	v, err := DoWithData(func() (int, error) {
+		calls++
+		return 42, nil
+	}, WithFaultInjection(0, chaos))
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+	assert.Equal(t, 1, calls)
+
+	assert.ErrorIs(t, Validate(WithFaultInjection(1.5, nil)), ErrInvalidOption)
+}
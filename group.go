@@ -0,0 +1,26 @@
+package retry
+
+import "context"
+
+// Group runs functions in goroutines, *errgroup.Group of golang.org/x/sync satisfies Group.
+type Group interface {
+	Go(func() error)
+}
+
+// GroupGo launches the retried function in the group.
+// Pass the context returned by errgroup.WithContext, so the retry stops as soon as another task of the group fails
+// (the context is the retry `Context` and it is passed to every attempt, see `DoContext`).
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	for _, url := range urls {
+//		url := url
+//		retry.GroupGo(ctx, g, func(ctx context.Context) error {
+//			return fetch(ctx, url)
+//		}, retry.Attempts(3))
+//	}
+//	err := g.Wait()
+func GroupGo(ctx context.Context, g Group, retryableFunc RetryableFuncContext, opts ...Option) {
+	g.Go(func() error {
+		return DoContext(ctx, retryableFunc, opts...)
+	})
+}
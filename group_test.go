@@ -0,0 +1,37 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type syncGroup struct {
+	errs []error
+}
+
+func (g *syncGroup) Go(f func() error) {
+	g.errs = append(g.errs, f())
+}
+
+func TestGroupGo(t *testing.T) {
+	g := &syncGroup{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	calls := 0
+	GroupGo(ctx, g, func(ctx context.Context) error {
+		calls++
+		if calls < 2 {
+			return errors.New("test")
+		}
+		return nil
+	}, Delay(0))
+	assert.Equal(t, []error{nil}, g.errs)
+
+	cancel()
+	GroupGo(ctx, g, func(ctx context.Context) error { return nil })
+	assert.ErrorIs(t, g.errs[1], context.Canceled)
+}
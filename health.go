@@ -0,0 +1,48 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+// WithHealthCheck replaces the delay between attempts by probing the health of the dependency:
+// after a failed attempt, the check is called every interval and the next attempt is made
+// as soon as it reports healthy, so the expensive operation isn't re-attempted blindly while the dependency is down.
+// The check gets the retry context, it should be cheap (e.g. a health endpoint) and respect the context.
+//
+//	retry.Do(
+//		func() error {
+//			return uploadLargeFile()
+//		},
+//		retry.WithHealthCheck(func(ctx context.Context) bool {
+//			return ping(ctx) == nil
+//		}, time.Second),
+//	)
+func WithHealthCheck(check func(ctx context.Context) bool, interval time.Duration) Option {
+	return func(c *Config) {
+		c.describe("WithHealthCheck", check, interval)
+		if check == nil || interval <= 0 {
+			c.invalid("WithHealthCheck needs a check and a positive interval, got %v", interval)
+			return
+		}
+		c.extend().healthCheck = check
+		c.extend().healthCheckInterval = interval
+	}
+}
+
+// waitHealthy probes the health check every interval until it reports healthy,
+// returns false when the context is done in the meantime
+func (c *Config) waitHealthy() bool {
+	var waited time.Duration
+	for {
+		waited += c.ext.healthCheckInterval
+		c.ext.onDelay(c.attempted, c.ext.healthCheckInterval)
+		c.ext.recorder.recordDelay(c, waited)
+		if !c.sleep(c.ext.healthCheckInterval) {
+			return false
+		}
+		if c.ext.healthCheck(c.context) {
+			return true
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHealthCheck(t *testing.T) {
+	probes := 0
+	recorder := &Recorder{}
+	calls := 0
+	err := Do(func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("test")
+		}
+		return nil
+	},
+		Delay(time.Hour),
+		WithRecorder(recorder),
+		WithHealthCheck(func(ctx context.Context) bool {
+			probes++
+			return probes == 3
+		}, time.Millisecond),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, 3, probes)
+	assert.Equal(t, 3*time.Millisecond, recorder.Attempts()[0].Delay)
+}
+
+func TestWithHealthCheckCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Do(func() error { return errors.New("test") },
+		Context(ctx),
+		LastErrorOnly(true),
+		WithHealthCheck(func(ctx context.Context) bool {
+			cancel()
+			return false
+		}, time.Millisecond),
+	)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	assert.ErrorIs(t, Validate(WithHealthCheck(nil, time.Second)), ErrInvalidOption)
+}
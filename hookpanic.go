@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// HookPanicError is returned (wrapped by `Unrecoverable`) when a hook of the retry
+// (OnRetry, RetryIf or DelayType) panics: the retry stops instead of crashing the caller.
+//
+//	var hookPanic *retry.HookPanicError
+//	if errors.As(err, &hookPanic) {
+//		log.Printf("%s panicked: %v\n%s", hookPanic.Hook, hookPanic.Value, hookPanic.Stack)
+//	}
+type HookPanicError struct {
+	Hook  string      // name of the option of the hook, e.g. "OnRetry"
+	Value interface{} // value passed to panic
+	Stack []byte      // stack trace of the panicking goroutine
+}
+
+func (e *HookPanicError) Error() string {
+	return fmt.Sprintf("retry: %s panicked: %v", e.Hook, e.Value)
+}
+
+// Unwrap returns the value passed to panic if it is an error
+func (e *HookPanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// recoverHook turns a panic of the hook into a panic with *HookPanicError, recovered by containHookPanics
+func recoverHook(hook string) {
+	if r := recover(); r != nil {
+		panic(&HookPanicError{Hook: hook, Value: r, Stack: debug.Stack()})
+	}
+}
+
+func (c *Config) callOnRetry(n uint, err error) {
+	defer recoverHook("OnRetry")
+	c.onRetry(n, err)
+}
+
+func (c *Config) callRetryIf(err error) bool {
+	defer recoverHook("RetryIf")
+	return c.retryIf(err)
+}
+
+func (c *Config) callDelayType(n uint, err error) time.Duration {
+	defer recoverHook("DelayType")
+	return c.delayType(n, err, c)
+}
+
+// containHookPanics runs the retry loop, a panic of a hook stops it with an unrecoverable error.
+// Panics of the retried function are not recovered.
+func containHookPanics[T any, F caller[T]](config *Config, retryableFunc F) (t T, err error) {
+	defer catchHookPanic(&err)
+	return retryLoop[T](config, retryableFunc)
+}
+
+// containHookPanic calls f (calling hooks outside of the retry loop), a panic of a hook is returned as an unrecoverable error
+func containHookPanic(f func()) (err error) {
+	defer catchHookPanic(&err)
+	f()
+	return nil
+}
+
+// catchHookPanic recovers a panic of a hook into *err as an unrecoverable error, other panics go on.
+// It must be deferred.
+func catchHookPanic(err *error) {
+	if r := recover(); r != nil {
+		hookPanic, ok := r.(*HookPanicError)
+		if !ok {
+			panic(r)
+		}
+		*err = Unrecoverable(hookPanic)
+	}
+}
@@ -0,0 +1,96 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHookPanic(t *testing.T) {
+	hooks := map[string]Option{
+		"OnRetry":   OnRetry(func(n uint, err error) { panic("logger failed") }),
+		"RetryIf":   RetryIf(func(err error) bool { panic("logger failed") }),
+		"DelayType": DelayType(func(n uint, err error, config *Config) time.Duration { panic("logger failed") }),
+	}
+	for hook, opt := range hooks {
+		t.Run(hook, func(t *testing.T) {
+			var count int
+			err := Do(
+				func() error {
+					count++
+					return errors.New("test")
+				},
+				Attempts(3),
+				opt,
+			)
+			assert.Equal(t, 1, count)
+			assert.True(t, IsUnrecoverable(err))
+
+			var hookPanic *HookPanicError
+			if assert.ErrorAs(t, err, &hookPanic) {
+				assert.Equal(t, hook, hookPanic.Hook)
+				assert.Equal(t, "logger failed", hookPanic.Value)
+				assert.NotEmpty(t, hookPanic.Stack)
+				assert.Equal(t, "retry: "+hook+" panicked: logger failed", hookPanic.Error())
+			}
+		})
+	}
+}
+
+func TestHookPanicError(t *testing.T) {
+	cause := errors.New("cause")
+	_, err := DoWithData(
+		func() (int, error) { return 0, errors.New("test") },
+		Attempts(0),
+		OnRetry(func(n uint, err error) { panic(cause) }),
+	)
+	assert.ErrorIs(t, err, cause)
+}
+
+func TestRetryableFuncPanicNotRecovered(t *testing.T) {
+	assert.PanicsWithValue(t, "test", func() {
+		_ = Do(func() error { panic("test") })
+	})
+}
+
+func TestHookPanicOutsideDo(t *testing.T) {
+	panicking := DelayType(func(n uint, err error, config *Config) time.Duration { panic("logger failed") })
+
+	t.Run("Stepper", func(t *testing.T) {
+		next, stop, err := Stepper(Attempts(3), OnRetry(func(n uint, err error) { panic("logger failed") }))
+		assert.NoError(t, err)
+		defer stop()
+		_, ok := next(errors.New("test"))
+		assert.False(t, ok)
+		_, ok = next(errors.New("test"))
+		assert.False(t, ok, "stopped for good")
+	})
+
+	t.Run("Plan", func(t *testing.T) {
+		delays, err := Plan(Attempts(3), panicking)
+		assert.Nil(t, delays)
+		assert.True(t, IsUnrecoverable(err))
+		var hookPanic *HookPanicError
+		if assert.ErrorAs(t, err, &hookPanic) {
+			assert.Equal(t, "DelayType", hookPanic.Hook)
+		}
+	})
+
+	t.Run("Retrier", func(t *testing.T) {
+		r := NewRetrier(Attempts(1), StatefulBackOff(true), panicking)
+		assert.Error(t, r.Do(func() error { return errors.New("test") }))
+
+		var count int
+		err := r.Do(func() error {
+			count++
+			return nil
+		})
+		assert.Equal(t, 0, count)
+		var hookPanic *HookPanicError
+		if assert.ErrorAs(t, err, &hookPanic) {
+			assert.Equal(t, "DelayType", hookPanic.Hook)
+		}
+	})
+}
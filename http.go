@@ -0,0 +1,126 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// HTTPError represents an unsuccessful HTTP response
+// which can be classified by `RetryIfHTTPStatus`
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error returns the status code, status text and the body of the response
+func (e *HTTPError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("HTTP %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("HTTP %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Body)
+}
+
+// DefaultRetryableHTTPStatuses are used by `RetryIfHTTPStatus` when no status codes are given
+var DefaultRetryableHTTPStatuses = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryIfHTTPStatus returns a RetryIfFunc which retries `*HTTPError` only for the given status codes
+// (`DefaultRetryableHTTPStatuses` if none are given).
+// Errors not carrying an HTTP status (e.g. network errors) are classified by `IsRetryable`.
+//
+//	retry.Do(
+//		func() error {
+//			resp, err := http.Get(url)
+//			if err != nil {
+//				return err
+//			}
+//			defer resp.Body.Close()
+//			if resp.StatusCode != http.StatusOK {
+//				return &retry.HTTPError{StatusCode: resp.StatusCode}
+//			}
+//			return nil
+//		},
+//		retry.RetryIf(retry.RetryIfHTTPStatus(429, 503)),
+//	)
+func RetryIfHTTPStatus(codes ...int) RetryIfFunc {
+	if len(codes) == 0 {
+		codes = DefaultRetryableHTTPStatuses
+	}
+	retryable := make(map[int]struct{}, len(codes))
+	for _, code := range codes {
+		retryable[code] = struct{}{}
+	}
+
+	return func(err error) bool {
+		var httpErr *HTTPError
+		if !IsRecoverable(err) || !errors.As(err, &httpErr) {
+			return IsRetryable(err)
+		}
+
+		_, ok := retryable[httpErr.StatusCode]
+		return ok
+	}
+}
+
+// MaxRetryAfter caps the delays returned by `ParseRetryAfter`,
+// so a misbehaving server can't stall a retry for days.
+// The delays of `RetryAfter` errors are also capped by the `MaxDelay` option of the retry.
+const MaxRetryAfter = 24 * time.Hour
+
+// ParseRetryAfter parses the value of the Retry-After header of an HTTP response
+// in both formats, delta-seconds ("120") and HTTP-date ("Wed, 21 Oct 2015 07:28:00 GMT"),
+// into the delay relative to now (dates in the past are 0), capped by `MaxRetryAfter`.
+// It returns false when the header is empty or invalid.
+//
+//	if d, ok := retry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+//		...
+//	}
+func ParseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if seconds, ok := parseDeltaSeconds(header); ok {
+		d = seconds
+	} else if at, err := http.ParseTime(header); err == nil {
+		d = at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+	} else {
+		return 0, false
+	}
+
+	if d > MaxRetryAfter {
+		d = MaxRetryAfter
+	}
+	return d, true
+}
+
+// parseDeltaSeconds parses non-negative integer seconds, saturating instead of overflowing
+func parseDeltaSeconds(s string) (time.Duration, bool) {
+	const maxSeconds = int64(math.MaxInt64 / time.Second)
+	var seconds int64
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, false
+		}
+		if seconds <= maxSeconds {
+			seconds = seconds*10 + int64(c-'0')
+		}
+	}
+	if seconds > maxSeconds {
+		return math.MaxInt64, true
+	}
+	return time.Duration(seconds) * time.Second, true
+}
@@ -0,0 +1,71 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPError(t *testing.T) {
+	assert.Equal(t, "HTTP 503 Service Unavailable", (&HTTPError{StatusCode: 503}).Error())
+	assert.Equal(t, "HTTP 429 Too Many Requests: slow down", (&HTTPError{StatusCode: 429, Body: "slow down"}).Error())
+}
+
+func TestRetryIfHTTPStatus(t *testing.T) {
+	defaults := RetryIfHTTPStatus()
+	assert.True(t, defaults(&HTTPError{StatusCode: http.StatusServiceUnavailable}))
+	assert.True(t, defaults(fmt.Errorf("wrap: %w", &HTTPError{StatusCode: http.StatusTooManyRequests})))
+	assert.False(t, defaults(&HTTPError{StatusCode: http.StatusNotImplemented}))
+	assert.False(t, defaults(Unrecoverable(&HTTPError{StatusCode: http.StatusServiceUnavailable})))
+	assert.True(t, defaults(errors.New("connection refused")))
+
+	only429 := RetryIfHTTPStatus(http.StatusTooManyRequests)
+	assert.True(t, only429(&HTTPError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, only429(&HTTPError{StatusCode: http.StatusServiceUnavailable}))
+
+	attempts := 0
+	err := Do(
+		func() error {
+			attempts++
+			if attempts < 3 {
+				return &HTTPError{StatusCode: http.StatusBadGateway}
+			}
+			return &HTTPError{StatusCode: http.StatusBadRequest}
+		},
+		RetryIf(RetryIfHTTPStatus()),
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2015, time.October, 21, 7, 28, 0, 0, time.UTC)
+	tests := []struct {
+		header string
+		want   time.Duration
+		ok     bool
+	}{
+		{"120", 2 * time.Minute, true},
+		{" 0 ", 0, true},
+		{"Wed, 21 Oct 2015 07:30:00 GMT", 2 * time.Minute, true},
+		{"Wed, 21 Oct 2015 07:00:00 GMT", 0, true},
+		{"Wednesday, 21-Oct-15 07:29:00 GMT", time.Minute, true},
+		{"999999999999999999999", MaxRetryAfter, true},
+		{"Thu, 22 Oct 2015 07:28:01 GMT", MaxRetryAfter, true},
+		{"", 0, false},
+		{"-1", 0, false},
+		{"+5", 0, false},
+		{"1.5", 0, false},
+		{"tomorrow", 0, false},
+	}
+	for _, tt := range tests {
+		d, ok := ParseRetryAfter(tt.header, now)
+		assert.Equal(t, tt.ok, ok, tt.header)
+		assert.Equal(t, tt.want, d, tt.header)
+	}
+}
@@ -0,0 +1,42 @@
+package retry
+
+import (
+	"errors"
+	"io"
+)
+
+// IsTransientIOError checks if error is a transient stream I/O error:
+// an unexpected EOF, a short write or a connection reset / broken pipe in the middle of the stream.
+// It can be used directly as RetryIfFunc.
+func IsTransientIOError(err error) bool {
+	if !IsRecoverable(err) {
+		return false
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.ErrShortWrite) ||
+		IsConnectionReset(err) ||
+		IsBrokenPipe(err)
+}
+
+// DoCopy retries an io.Copy-style operation on transient stream I/O errors (see `IsTransientIOError`).
+// The copy function must open a fresh reader and writer (or seek them back) on every call,
+// because a failed attempt may have already consumed part of the stream.
+// The default RetryIf may be overridden by opts.
+//
+//	written, err := retry.DoCopy(
+//		func() (int64, error) {
+//			resp, err := http.Get(url)
+//			if err != nil {
+//				return 0, err
+//			}
+//			defer resp.Body.Close()
+//			if _, err := f.Seek(0, io.SeekStart); err != nil {
+//				return 0, retry.Unrecoverable(err)
+//			}
+//			return io.Copy(f, resp.Body)
+//		},
+//	)
+func DoCopy(copyFunc func() (int64, error), opts ...Option) (int64, error) {
+	return DoWithData(copyFunc, append([]Option{RetryIf(IsTransientIOError)}, opts...)...)
+}
@@ -0,0 +1,67 @@
+//go:build !plan9
+
+package retry
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTransientIOError(t *testing.T) {
+	assert.True(t, IsTransientIOError(io.ErrUnexpectedEOF))
+	assert.True(t, IsTransientIOError(fmt.Errorf("read body: %w", io.ErrUnexpectedEOF)))
+	assert.True(t, IsTransientIOError(io.ErrShortWrite))
+	assert.True(t, IsTransientIOError(syscall.ECONNRESET))
+	assert.True(t, IsTransientIOError(syscall.EPIPE))
+	assert.False(t, IsTransientIOError(io.EOF))
+	assert.False(t, IsTransientIOError(errors.New("other")))
+	assert.False(t, IsTransientIOError(Unrecoverable(io.ErrUnexpectedEOF)))
+}
+
+type flakyReader struct {
+	r    io.Reader
+	fail bool
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	if f.fail {
+		return 0, io.ErrUnexpectedEOF
+	}
+	return f.r.Read(p)
+}
+
+func TestDoCopy(t *testing.T) {
+	var dst bytes.Buffer
+	attempts := 0
+	written, err := DoCopy(
+		func() (int64, error) {
+			attempts++
+			dst.Reset()
+			return io.Copy(&dst, &flakyReader{r: strings.NewReader("hello"), fail: attempts < 3})
+		},
+		Delay(time.Nanosecond),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), written)
+	assert.Equal(t, "hello", dst.String())
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	_, err = DoCopy(
+		func() (int64, error) {
+			attempts++
+			return 0, errors.New("permanent")
+		},
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
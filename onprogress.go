@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"math"
+	"time"
+)
+
+// maxEstimatedDelays limits count of delays evaluated by the estimate of Progress.MaxRemainingWait
+const maxEstimatedDelays = 1000
+
+// Progress of a retry reported by `OnProgress`
+type Progress struct {
+	// Attempt is count of attempts made so far, including the ones before `Resume`
+	Attempt uint
+	// Attempts is the maximal count of attempts, 0 when retrying until success
+	Attempts uint
+	// Remaining is count of attempts left, 0 when retrying until success
+	Remaining uint
+	// Elapsed is the time since the start of the retry
+	Elapsed time.Duration
+	// NextDelay is the delay before the next attempt
+	NextDelay time.Duration
+	// MaxRemainingWait is the estimated maximum of the sum of all remaining delays
+	// (random delays are taken at their maximum), limited by the deadline of the context.
+	// It is -1 when unknown, e.g. when retrying until success.
+	MaxRemainingWait time.Duration
+	// Err is the error of the last attempt
+	Err error
+}
+
+// OnProgress function callback is called after each failed attempt which will be retried,
+// right before sleeping, with the Progress of the retry, so CLIs and UIs can show
+// meaningful feedback like "retrying (3/10, up to 40s left)…".
+//
+//	retry.Do(
+//		func() error { ... },
+//		retry.Attempts(10),
+//		retry.OnProgress(func(p retry.Progress) {
+//			fmt.Printf("retrying (%d/%d, up to %v left)…\n", p.Attempt, p.Attempts, p.MaxRemainingWait)
+//		}),
+//	)
+func OnProgress(onProgress func(Progress)) Option {
+	if onProgress == nil {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("OnProgress", onProgress)
+		c.extend().onProgress = onProgress
+	}
+}
+
+// reportProgress calls OnProgress (if any) before the delay d with index n.
+// used is count of attempts counted against Attempts, ignored when retrying until success.
+func (c *Config) reportProgress(n, used uint, d time.Duration, err error) {
+	if c.ext.onProgress == nil {
+		return
+	}
+
+	now := c.clock.Now()
+	p := Progress{
+		Attempt:          c.attempted,
+		Attempts:         c.attempts,
+		Elapsed:          now.Sub(c.startedAt),
+		NextDelay:        d,
+		MaxRemainingWait: -1,
+		Err:              err,
+	}
+	if c.attempts != 0 {
+		if used < c.attempts {
+			p.Remaining = c.attempts - used
+		}
+		p.MaxRemainingWait = c.estimateRemainingWait(n, p.Remaining, d)
+	}
+	if deadline, ok := c.context.Deadline(); ok {
+		if left := deadline.Sub(now); left < 0 {
+			p.MaxRemainingWait = 0
+		} else if p.MaxRemainingWait < 0 || left < p.MaxRemainingWait {
+			p.MaxRemainingWait = left
+		}
+	}
+
+	c.ext.onProgress(p)
+}
+
+// estimateRemainingWait sums the delay d with index n and the maximal delays before the remaining attempts after it,
+// returns -1 when the sum can't be estimated
+func (c *Config) estimateRemainingWait(n, remaining uint, d time.Duration) time.Duration {
+	// the delays are evaluated on a copy of the config, so the retry isn't affected,
+	// with random numbers at their maximum
+	estimate := *c
+	estimate.rand, estimate.randInjected = maxRandom{}, true
+	ext := *c.ext
+	ext.jitterRecording, ext.jitterReplay = nil, nil
+	estimate.ext = &ext
+
+	total := uint64(d)
+	for k := uint(1); k < remaining; k++ {
+		var next time.Duration
+		switch {
+		case k <= maxEstimatedDelays:
+			next = delay(&estimate, n+k, nil)
+		case c.maxDelay > 0:
+			next = c.maxDelay
+		default:
+			return -1
+		}
+		if next == StopDelay {
+			break
+		}
+		total += uint64(next)
+		if total > math.MaxInt64 {
+			return math.MaxInt64
+		}
+	}
+	return time.Duration(total)
+}
+
+// maxRandom is a randomGenerator always drawing the maximal number
+type maxRandom struct{}
+
+func (maxRandom) Float64() float64 {
+	return math.Nextafter(1, 0)
+}
+
+func (maxRandom) Int63n(n int64) int64 {
+	return n - 1
+}
@@ -0,0 +1,77 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOnProgress(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC)}
+	testErr := errors.New("test")
+
+	var progress []Progress
+	err := Do(
+		func() error { return testErr },
+		Attempts(4),
+		Delay(time.Second),
+		DelayType(BackOffDelay),
+		WithClock(clock),
+		OnProgress(func(p Progress) { progress = append(progress, p) }),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []Progress{
+		{Attempt: 1, Attempts: 4, Remaining: 3, Elapsed: 0, NextDelay: time.Second, MaxRemainingWait: 7 * time.Second, Err: testErr},
+		{Attempt: 2, Attempts: 4, Remaining: 2, Elapsed: time.Second, NextDelay: 2 * time.Second, MaxRemainingWait: 6 * time.Second, Err: testErr},
+		{Attempt: 3, Attempts: 4, Remaining: 1, Elapsed: 3 * time.Second, NextDelay: 4 * time.Second, MaxRemainingWait: 4 * time.Second, Err: testErr},
+	}, progress)
+}
+
+func TestOnProgressRandomDelays(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC)}
+
+	var first Progress
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(6),
+		MaxJitter(time.Second),
+		WithClock(clock),
+		OnProgress(func(p Progress) {
+			if p.Attempt == 1 {
+				first = p
+			}
+		}),
+	)
+	assert.Error(t, err)
+
+	var total time.Duration
+	for _, d := range clock.delays {
+		total += d
+	}
+	assert.GreaterOrEqual(t, first.MaxRemainingWait, total)
+}
+
+func TestOnProgressUntilSuccess(t *testing.T) {
+	var progress []Progress
+	var count int
+	err := Do(
+		func() error {
+			count++
+			if count < 3 {
+				return errors.New("test")
+			}
+			return nil
+		},
+		Attempts(0),
+		Delay(0),
+		OnProgress(func(p Progress) { progress = append(progress, p) }),
+	)
+	assert.NoError(t, err)
+	if assert.Len(t, progress, 2) {
+		assert.Equal(t, uint(2), progress[1].Attempt)
+		assert.Equal(t, uint(0), progress[1].Remaining)
+		assert.Equal(t, time.Duration(-1), progress[1].MaxRemainingWait)
+	}
+}
@@ -2,8 +2,10 @@ package retry
 
 import (
 	"context"
+	"encoding/binary"
+	"hash/fnv"
 	"math"
-	"math/rand"
+	"os"
 	"time"
 )
 
@@ -16,16 +18,52 @@ type RetryIfFunc func(error) bool
 // n = count of attempts
 type OnRetryFunc func(n uint, err error)
 
+// 重试被中止时做什么. reason 是中止的原因, lastErr 是最后一次执行的错误
+// Function signature of OnAbort function
+// reason = context error, unrecoverable error or ErrNoProgress
+// lastErr = error of the last attempt (nil when no attempt failed)
+// n = count of attempts
+type OnAbortFunc func(reason error, lastErr error, n uint)
+
+// 每次等待之前调用, d 是最终的 delay
+// Function signature of OnDelay function
+// n = count of attempts
+// d = the delay before the next attempt
+type OnDelayFunc func(n uint, d time.Duration)
+
+// 每次执行失败之后调用, 用于释放这次执行的资源
+// Function signature of OnCleanup function
+// n = count of attempts (including the failed one)
+// err = error of the failed attempt
+type OnCleanupFunc func(n uint, err error)
+
+// 每次执行之前调用, 包括第一次
+// Function signature of BeforeAttempt function
+// n = number of the attempt about to start, starting from 1
+type BeforeAttemptFunc func(n uint)
+
 // 当 执行函数 对某 err 失败了 n 次时, 此函数会返回下次 delay 的 time.Duration
 // 用途不明确
 // DelayTypeFunc is called to return the next delay to wait after the retriable function fails on `err` after `n` attempts.
 type DelayTypeFunc func(n uint, err error, config *Config) time.Duration
 
+// StopDelay may be returned by a DelayTypeFunc to stop retrying,
+// e.g. when the delay logic finds out the server will never accept the request again.
+// The retry ends as if RetryIf returned false for the last error.
+const StopDelay time.Duration = math.MinInt64
+
 // Timer represents the timer used to track time for a retry.
 type Timer interface {
 	After(time.Duration) <-chan time.Time
 }
 
+// Clock represents the source of time for a retry.
+// Besides sleeping (see Timer) it is used for timestamps, schedules and time windows.
+type Clock interface {
+	Timer
+	Now() time.Time
+}
+
 type Config struct {
 	attempts                      uint            // 重试几次
 	attemptsForError              map[error]uint  // 各错误重试几次
@@ -36,11 +74,108 @@ type Config struct {
 	retryIf                       RetryIfFunc     // 什么时机 retry
 	delayType                     DelayTypeFunc   // todo 有什么用
 	lastErrorOnly                 bool            // 只记录最后的 error
+	wrapContextErrorWithLastError bool            // todo 有什么用
 	context                       context.Context // 上下文
 	timer                         Timer           // todo 貌似只有单测使用
-	wrapContextErrorWithLastError bool            // todo 有什么用
+	clock                         Clock           // 当前时间, 单测可以替换
 
 	maxBackOffN uint // 最多 backoff n 次
+
+	ext *configExt // 不常用的配置, 见 extend
+
+	// 以下是本次执行的状态
+	progress      *progressTracker // 记录最后一次进展的时间
+	budget        *budgetTracker   // 当前执行剩余的次数, 用于 BudgetFromContext
+	attempted     uint             // 已经执行的次数, 包括 Resume 之前的
+	exhausted     bool             // 是否用完了所有的 attempts
+	randInjected  bool             // rand 来自 WithRandSource
+	jitterPos     int              // 本次执行中已经使用的 jitter 个数
+	traceContext  context.Context  // runtime/trace 开启时, 本次执行的 task
+	delaySchedule []time.Duration  // 确定性的 DelayType 预先计算好的 delay, 见 precomputeDelays
+	rand          randomGenerator  // 本次执行的随机数生成器, 见 random
+	startedAt     time.Time        // 开始重试的时间, 用于 Progress.Elapsed
+}
+
+// configExt is the part of Config set only by less common options.
+// All configs share defaultConfigExt until an option modifies it (see extend),
+// so every retry doesn't copy all of them.
+type configExt struct {
+	infos *[]OptionInfo // 不为 nil 时, Option 会记录自己的描述, 用于 Describe
+	err   error         // 第一个无效 Option 的错误, 有错误时不会执行
+
+	excludedErrors      map[error]bool    // 这些错误的重试不计入总的 attempts
+	initialDelay        time.Duration     // 第一次执行前延迟多久
+	immediateFirstRetry bool              // 第一次 retry 不延迟
+	minJitter           time.Duration     // 抖动的最小值, 见 JitterRange
+	proportionalJitter  float64           // 按 delay 的百分比增加的抖动
+	noDelay             bool              // 两次执行之间不等待
+	unrecoverableIf     RetryIfFunc       // 什么时机不再 retry, 优先于 retryIf
+	deadlineAware       bool              // 等待不超过 context 的 deadline
+	deadlineMargin      time.Duration     // 在 deadline 之前多久就返回
+	errorHistory        uint              // 无限重试时保留最近几个错误
+	successThreshold    uint              // 连续成功几次才算成功
+	onState             func(State)       // 每次等待之前保存状态
+	onAbort             OnAbortFunc       // 重试被中止时调用
+	onDelay             OnDelayFunc       // 每次等待之前调用
+	onCleanup           OnCleanupFunc     // 每次执行失败之后调用
+	beforeAttempt       BeforeAttemptFunc // 每次执行之前调用
+	onProgress          func(Progress)    // 每次等待之前报告进度
+	startAttempt        uint              // 从第几次开始, 用于 Resume
+	startAt             time.Time         // 第一次执行的时间, 用于 Resume
+	windows             []window          // 允许执行的时间窗口
+	noProgressTimeout   time.Duration     // 多久没有进展就放弃
+	recorder            *Recorder         // 记录每一次执行
+	recorderRetry       uint64            // 在 recorder 中标识这次重试, 0 表示还没有记录
+	stats               StatsCollector    // 收集执行的统计数据
+
+	jitterRecording *Recording // 记录随机的 jitter
+	jitterReplay    *Recording // 重放记录的 jitter
+
+	randomizationFactor float64 // backoff 的 delay 随机浮动的比例
+
+	delayOffset uint // 传给 DelayType 的 n 的偏移量, Retrier 用它在多次调用之间延续 backoff
+
+	concurrency     uint          // Each 同时重试几个 item
+	statefulBackOff bool          // Retrier 在两次调用之间也做 backoff
+	memoizeTTL      time.Duration // Retrier 在多长时间内返回上次成功的结果
+
+	singleflight    Singleflight // 相同 key 的并发重试共享一次执行
+	singleflightKey string
+
+	semaphore Semaphore // 每次执行前获取, 限制同时执行的次数
+	pacer     *Pacer    // 每次执行前等待, 限制执行的速率
+
+	bulkheadKey   string // 相同 key 的重试同时最多 bulkheadLimit 个
+	bulkheadLimit int
+
+	healthCheck         func(context.Context) bool // 代替 delay, 健康之后再重试
+	healthCheckInterval time.Duration
+
+	faultRate float64 // 成功的执行中有多少比例被替换为失败, 用于测试
+	faultErr  error   // 注入的错误
+
+	asyncHooks         bool          // OnRetry 和 OnDelay 在单独的 goroutine 中执行
+	signals            []os.Signal   // 收到这些信号时停止重试
+	hardAttemptTimeout time.Duration // 单次执行超过这个时间就放弃, 不再等待它返回
+}
+
+// extend returns the configExt of the config for modification,
+// the shared defaultConfigExt is copied on first use
+func (c *Config) extend() *configExt {
+	if c.ext == &defaultConfigExt {
+		ext := defaultConfigExt
+		c.ext = &ext
+	}
+	return c.ext
+}
+
+// extension returns the configExt of the config for reading, the defaults for a Config
+// which wasn't made by the options (e.g. passed to a DelayTypeFunc directly)
+func (c *Config) extension() *configExt {
+	if c.ext == nil {
+		return &defaultConfigExt
+	}
+	return c.ext
 }
 
 // Option represents an option for retry.
@@ -59,6 +194,7 @@ func emptyOption(c *Config) {
 // 外层函数传入的 lastErrorOnly 被内层闭包函数捕获
 func LastErrorOnly(lastErrorOnly bool) Option {
 	return func(c *Config) {
+		c.describe("LastErrorOnly", lastErrorOnly)
 		c.lastErrorOnly = lastErrorOnly
 	}
 }
@@ -67,6 +203,7 @@ func LastErrorOnly(lastErrorOnly bool) Option {
 // default is 10
 func Attempts(attempts uint) Option {
 	return func(c *Config) {
+		c.describe("Attempts", attempts)
 		c.attempts = attempts
 	}
 }
@@ -78,22 +215,98 @@ func Attempts(attempts uint) Option {
 // added in 4.3.0
 func AttemptsForError(attempts uint, err error) Option {
 	return func(c *Config) {
-		c.attemptsForError[err] = attempts
+		c.describe("AttemptsForError", attempts, err)
+		c.setAttemptsForError(err, attempts)
+	}
+}
+
+// SuccessThreshold sets count of consecutive successful calls required before the retry returns success.
+// It is useful for flaky health checks and eventually consistent reads.
+// A failure resets the counter and is counted against total retries, successful calls are not.
+// The delay between consecutive successful calls follows the DelayType.
+// default is 1
+func SuccessThreshold(successThreshold uint) Option {
+	if successThreshold == 0 {
+		successThreshold = 1
+	}
+	return func(c *Config) {
+		c.describe("SuccessThreshold", successThreshold)
+		c.extend().successThreshold = successThreshold
+	}
+}
+
+// AttemptsForErrorOnly sets count of retry in case execution results in given `err`
+// like AttemptsForError, but retries for the given `err` are NOT counted against total retries,
+// so "allow 20 throttling retries" works even with only 5 total attempts.
+// The retry will stop if the given retries are exhausted.
+func AttemptsForErrorOnly(attempts uint, err error) Option {
+	return func(c *Config) {
+		c.describe("AttemptsForErrorOnly", attempts, err)
+		c.setAttemptsForError(err, attempts)
+		if c.ext.excludedErrors == nil {
+			c.extend().excludedErrors = make(map[error]bool)
+		}
+		c.extend().excludedErrors[err] = true
+	}
+}
+
+// setAttemptsForError sets attempts for the error in a copy of the map (allocated on first use),
+// the map of the config may be shared with other configs
+func (c *Config) setAttemptsForError(err error, attempts uint) {
+	attemptsForError := make(map[error]uint, len(c.attemptsForError)+1)
+	for e, a := range c.attemptsForError {
+		attemptsForError[e] = a
 	}
+	attemptsForError[err] = attempts
+	c.attemptsForError = attemptsForError
 }
 
 // Delay set delay between retry
 // default is 100ms
 func Delay(delay time.Duration) Option {
 	return func(c *Config) {
+		c.describe("Delay", delay)
+		if delay < 0 {
+			c.invalid("Delay must not be negative, got %v", delay)
+			return
+		}
 		c.delay = delay
 	}
 }
 
+// InitialDelay set delay before the very first attempt,
+// e.g. when the resource is known not to be ready right after provisioning
+// default is 0 (no delay)
+func InitialDelay(initialDelay time.Duration) Option {
+	return func(c *Config) {
+		c.describe("InitialDelay", initialDelay)
+		if initialDelay < 0 {
+			c.invalid("InitialDelay must not be negative, got %v", initialDelay)
+			return
+		}
+		c.extend().initialDelay = initialDelay
+	}
+}
+
+// ImmediateFirstRetry makes the first retry happen without any delay,
+// subsequent retries follow the DelayType ("one fast retry, then back off")
+// default is false
+func ImmediateFirstRetry(immediateFirstRetry bool) Option {
+	return func(c *Config) {
+		c.describe("ImmediateFirstRetry", immediateFirstRetry)
+		c.extend().immediateFirstRetry = immediateFirstRetry
+	}
+}
+
 // MaxDelay set maximum delay between retry
 // does not apply by default
 func MaxDelay(maxDelay time.Duration) Option {
 	return func(c *Config) {
+		c.describe("MaxDelay", maxDelay)
+		if maxDelay < 0 {
+			c.invalid("MaxDelay must not be negative, got %v", maxDelay)
+			return
+		}
 		c.maxDelay = maxDelay
 	}
 }
@@ -101,39 +314,157 @@ func MaxDelay(maxDelay time.Duration) Option {
 // MaxJitter sets the maximum random Jitter between retries for RandomDelay
 func MaxJitter(maxJitter time.Duration) Option {
 	return func(c *Config) {
+		c.describe("MaxJitter", maxJitter)
+		if maxJitter < 0 {
+			c.invalid("MaxJitter must not be negative, got %v", maxJitter)
+			return
+		}
 		c.maxJitter = maxJitter
 	}
 }
 
+// JitterRange sets the range of the random Jitter between retries for RandomDelay,
+// e.g. JitterRange(2*time.Second, 5*time.Second) with DelayType(RandomDelay) waits a random 2-5 seconds.
+// It overrides MaxJitter.
+func JitterRange(min, max time.Duration) Option {
+	return func(c *Config) {
+		c.describe("JitterRange", min, max)
+		if min < 0 || max < min {
+			c.invalid("JitterRange must be 0 <= min <= max, got %v-%v", min, max)
+			return
+		}
+		c.extend().minJitter = min
+		c.maxJitter = max
+	}
+}
+
+// ProportionalJitter adds a random jitter of up to pct percent of every delay,
+// so the jitter scales with the backoff instead of being a fixed absolute cap which becomes irrelevant at large delays.
+// Use it with a DelayType without RandomDelay, e.g. ProportionalJitter(20) with BackOffDelay waits 1-1.2s, 2-2.4s, 4-4.8s, ...
+// MaxDelay is a hard cap, delays with the jitter don't exceed it.
+// The jitter may be recorded and replayed, see `RecordJitter` and `WithReplay`.
+// default is 0 (no proportional jitter)
+func ProportionalJitter(pct float64) Option {
+	return func(c *Config) {
+		c.describe("ProportionalJitter", pct)
+		if pct < 0 {
+			c.invalid("ProportionalJitter must not be negative, got %v", pct)
+			return
+		}
+		c.extend().proportionalJitter = pct
+	}
+}
+
+// NoDelay removes all waiting between attempts (including RetryAfter hints and the timer),
+// e.g. for purely in-memory operations like optimistic-concurrency updates.
+// The context is still checked between attempts.
+func NoDelay() Option {
+	return func(c *Config) {
+		c.describe("NoDelay")
+		c.extend().noDelay = true
+	}
+}
+
 // DelayType set type of the delay between retries
 // default is BackOff
+//
+// When the error returned by the retried function implements `RetryAfter() time.Duration`
+// (or `NextAttemptAt() time.Time`, see `RetryAt`),
+// the suggested delay (capped by MaxDelay) is used instead of the DelayType.
 func DelayType(delayType DelayTypeFunc) Option {
 	if delayType == nil {
 		return emptyOption
 	}
 	return func(c *Config) {
+		c.describe("DelayType", delayType)
 		c.delayType = delayType
 	}
 }
 
 // BackOffDelay is a DelayType which increases delay between consecutive retries
+// The delay is randomized when RandomizationFactor is set.
+// It stores the delay (at least 1ns) and the maximal exponent into the config on first use,
+// see BackOffDelayPure for a DelayType which doesn't modify the config.
 func BackOffDelay(n uint, _ error, config *Config) time.Duration {
-	// 1 << 63 would overflow signed int64 (time.Duration), thus 62.
-	const max uint = 62
-
 	if config.maxBackOffN == 0 {
 		if config.delay <= 0 {
 			config.delay = 1
 		}
 
-		config.maxBackOffN = max - uint(math.Floor(math.Log2(float64(config.delay))))
+		config.maxBackOffN = maxBackOffN(config.delay)
 	}
 
 	if n > config.maxBackOffN {
 		n = config.maxBackOffN
 	}
 
-	return config.delay << n
+	return randomize(config.delay<<n, config.extension().randomizationFactor, config)
+}
+
+// BackOffDelayPure is BackOffDelay which doesn't write anything back into the config,
+// so the config can be safely shared and reused. It is the default (combined with RandomDelay).
+func BackOffDelayPure(n uint, _ error, config *Config) time.Duration {
+	return randomize(backOff(config.delay, n), config.extension().randomizationFactor, config)
+}
+
+// backOff returns delay<<n with n capped so the delay doesn't overflow, delay is at least 1ns
+func backOff(delay time.Duration, n uint) time.Duration {
+	if delay <= 0 {
+		delay = 1
+	}
+	if maxN := maxBackOffN(delay); n > maxN {
+		n = maxN
+	}
+	return delay << n
+}
+
+// maxBackOffN returns the maximal exponent of the positive delay which doesn't overflow
+func maxBackOffN(delay time.Duration) uint {
+	// 1 << 63 would overflow signed int64 (time.Duration), thus 62.
+	const max uint = 62
+
+	return max - uint(math.Floor(math.Log2(float64(delay))))
+}
+
+// randomize picks a delay uniformly from [d*(1-factor), d*(1+factor)]
+func randomize(d time.Duration, factor float64, config *Config) time.Duration {
+	if factor <= 0 {
+		return d
+	}
+
+	return config.jitter(func() time.Duration {
+		delta := factor * float64(d)
+		randomized := float64(d) - delta + config.random().Float64()*2*delta
+		if randomized >= math.MaxInt64 {
+			return math.MaxInt64
+		}
+		return time.Duration(randomized)
+	})
+}
+
+// RandomizationFactor randomizes each delay of BackOffDelay (and BackOffDelayPure), picking it uniformly from
+// [delay*(1-factor), delay*(1+factor)], as RandomizationFactor of cenkalti/backoff does.
+// Combine it with BackOffDelay alone, instead of the default combination with RandomDelay:
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.DelayType(retry.BackOffDelay),
+//		retry.RandomizationFactor(0.5),
+//	)
+//
+// The randomized delays may be recorded and replayed, see `RecordJitter` and `WithReplay`.
+// factor must be between 0 and 1, default is 0 (no randomization)
+func RandomizationFactor(factor float64) Option {
+	return func(c *Config) {
+		c.describe("RandomizationFactor", factor)
+		if factor < 0 || factor > 1 {
+			c.invalid("RandomizationFactor must be between 0 and 1, got %v", factor)
+			return
+		}
+		c.extend().randomizationFactor = factor
+	}
 }
 
 // FixedDelay is a DelayType which keeps delay the same through all iterations
@@ -142,18 +473,56 @@ func FixedDelay(_ uint, _ error, config *Config) time.Duration {
 }
 
 // RandomDelay is a DelayType which picks a random delay up to config.maxJitter
+// (from the range set by JitterRange)
+// The picked values may be recorded and replayed, see `RecordJitter` and `WithReplay`.
 func RandomDelay(_ uint, _ error, config *Config) time.Duration {
-	return time.Duration(rand.Int63n(int64(config.maxJitter)))
+	return config.jitter(func() time.Duration {
+		jitter := config.extension().minJitter
+		if span := config.maxJitter - jitter; span > 0 {
+			jitter += time.Duration(config.random().Int63n(int64(span)))
+		}
+		return jitter
+	})
+}
+
+// KeyedJitter returns a DelayType which picks a delay up to config.maxJitter (from the range set by JitterRange) derived from the key and the attempt number
+// instead of a random one. Each instance of a fleet of identical clients (keyed e.g. by hostname or shard ID)
+// gets a different but stable jitter, so the fleet de-synchronizes after a shared outage
+// and the delays stay reproducible per instance.
+//
+//	hostname, _ := os.Hostname()
+//	retry.DelayType(retry.CombineDelay(retry.BackOffDelay, retry.KeyedJitter(hostname)))
+func KeyedJitter(key string) DelayTypeFunc {
+	return func(n uint, _ error, config *Config) time.Duration {
+		minJitter := config.extension().minJitter
+		span := config.maxJitter - minJitter
+		if span <= 0 {
+			return minJitter
+		}
+
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(key))
+		var attempt [8]byte
+		binary.LittleEndian.PutUint64(attempt[:], uint64(n))
+		_, _ = h.Write(attempt[:])
+
+		return minJitter + time.Duration(h.Sum64()%uint64(span))
+	}
 }
 
 // CombineDelay is a DelayType the combines all of the specified delays into a new DelayTypeFunc
+// If any of the delays returns StopDelay, so does the combined one.
 func CombineDelay(delays ...DelayTypeFunc) DelayTypeFunc {
 	const maxInt64 = uint64(math.MaxInt64)
 
 	return func(n uint, err error, config *Config) time.Duration {
 		var total uint64
 		for _, delay := range delays {
-			total += uint64(delay(n, err, config))
+			d := delay(n, err, config)
+			if d == StopDelay {
+				return StopDelay
+			}
+			total += uint64(d)
 			if total > maxInt64 {
 				total = maxInt64
 			}
@@ -180,10 +549,110 @@ func OnRetry(onRetry OnRetryFunc) Option {
 		return emptyOption
 	}
 	return func(c *Config) {
+		c.describe("OnRetry", onRetry)
 		c.onRetry = onRetry
 	}
 }
 
+// OnAbort function callback is called once when the retry stops before running out of attempts:
+// when the context is done (reason is the context error, see `Context`),
+// when the error is unrecoverable (reason is the error, see `Unrecoverable` and `UnrecoverableIf`)
+// or when there is no progress (reason is ErrNoProgress, see `AbortIfNoProgress`).
+// It is not called on success, when attempts are exhausted or when RetryIf rejects the error.
+//
+// compensate aborted work example:
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.Context(ctx),
+//		retry.OnAbort(func(reason error, lastErr error, n uint) {
+//			rollback(reason)
+//		}),
+//	)
+func OnAbort(onAbort OnAbortFunc) Option {
+	if onAbort == nil {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("OnAbort", onAbort)
+		c.extend().onAbort = onAbort
+	}
+}
+
+// OnDelay function callback is called just before sleeping between attempts with the final delay,
+// i.e. after the DelayType, RetryAfter hints, MaxDelay and ImmediateFirstRetry were applied,
+// so the pacing can be observed (e.g. exported as a metric) without wrapping the DelayType.
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.OnDelay(func(n uint, d time.Duration) {
+//			delayHistogram.Observe(d.Seconds())
+//		}),
+//	)
+func OnDelay(onDelay OnDelayFunc) Option {
+	if onDelay == nil {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("OnDelay", onDelay)
+		c.extend().onDelay = onDelay
+	}
+}
+
+// OnCleanup function callback is called after every failed attempt (including the last one),
+// before anything else happens (RetryIf, OnRetry, the delay), so the next attempt never starts before it.
+// It is a place to release resources of the attempt (close bodies, roll back partial state)
+// instead of cramming it into the retried function.
+//
+//	var resp *http.Response
+//	retry.Do(
+//		func() error {
+//			var err error
+//			resp, err = http.Get(url)
+//			...
+//		},
+//		retry.OnCleanup(func(n uint, err error) {
+//			if resp != nil {
+//				resp.Body.Close()
+//			}
+//		}),
+//	)
+func OnCleanup(onCleanup OnCleanupFunc) Option {
+	if onCleanup == nil {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("OnCleanup", onCleanup)
+		c.extend().onCleanup = onCleanup
+	}
+}
+
+// BeforeAttempt function callback is called before every attempt, including the first one,
+// e.g. to refresh a token, re-resolve an endpoint or rotate credentials, which OnRetry
+// (called only after failures) cannot do for the first attempt.
+//
+//	retry.Do(
+//		func() error {
+//			return client.Call(token)
+//		},
+//		retry.BeforeAttempt(func(n uint) {
+//			token = tokens.Fresh()
+//		}),
+//	)
+func BeforeAttempt(beforeAttempt BeforeAttemptFunc) Option {
+	if beforeAttempt == nil {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("BeforeAttempt", beforeAttempt)
+		c.extend().beforeAttempt = beforeAttempt
+	}
+}
+
 // RetryIf controls whether a retry should be attempted after an error
 // (assuming there are any retry attempts remaining)
 //
@@ -201,8 +670,8 @@ func OnRetry(onRetry OnRetryFunc) Option {
 //		})
 //	)
 //
-// By default RetryIf stops execution if the error is wrapped using `retry.Unrecoverable`,
-// so above example may also be shortened to:
+// By default RetryIf (see `IsRetryable`) stops execution if the error is wrapped using `retry.Unrecoverable`
+// or reports `Retryable() == false`, so above example may also be shortened to:
 //
 //	retry.Do(
 //		func() error {
@@ -214,10 +683,78 @@ func RetryIf(retryIf RetryIfFunc) Option {
 		return emptyOption
 	}
 	return func(c *Config) {
+		c.describe("RetryIf", retryIf)
 		c.retryIf = retryIf
 	}
 }
 
+// UnrecoverableIf marks errors matching the predicate as unrecoverable,
+// so the retry loop stops immediately regardless of RetryIf.
+// This is useful when wrapping third-party code that can't return `retry.Unrecoverable` itself.
+//
+//	retry.Do(
+//		func() error {
+//			return client.Call()
+//		},
+//		retry.UnrecoverableIf(func(err error) bool {
+//			return errors.Is(err, client.ErrUnauthorized)
+//		}),
+//	)
+func UnrecoverableIf(unrecoverableIf RetryIfFunc) Option {
+	if unrecoverableIf == nil {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("UnrecoverableIf", unrecoverableIf)
+		c.extend().unrecoverableIf = unrecoverableIf
+	}
+}
+
+// StatefulBackOff makes a Retrier back off between separate calls too (it has no effect on `retry.Do`).
+// When previous calls failed, the next call waits the current backoff delay before its first attempt
+// and a successful call only halves the accumulated backoff state instead of resetting it,
+// so a polling worker calling `Do` once per cycle backs off properly across cycles.
+//
+// default is false
+//
+//	r := retry.NewRetrier(
+//		retry.Attempts(1),
+//		retry.StatefulBackOff(true),
+//	)
+//	for {
+//		_ = r.Do(poll)
+//	}
+func StatefulBackOff(statefulBackOff bool) Option {
+	return func(c *Config) {
+		c.describe("StatefulBackOff", statefulBackOff)
+		c.extend().statefulBackOff = statefulBackOff
+	}
+}
+
+// Memoize makes a Retrier return the value of its last successful call for the ttl,
+// instead of executing the function again (it has no effect on `retry.Do`),
+// e.g. for an expensive idempotent lookup guarded by retries.
+// The value is shared by all calls of the Retrier, so use a Retrier per lookup.
+// Calls with a different type of the value than the memoized one are executed.
+//
+// default is 0 (no memoization)
+//
+//	r := retry.NewRetrier(
+//		retry.Attempts(3),
+//		retry.Memoize(time.Minute),
+//	)
+//	config, err := retry.RetrierDoWithData(r, fetchConfig)
+func Memoize(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.describe("Memoize", ttl)
+		if ttl < 0 {
+			c.invalid("Memoize must not be negative, got %v", ttl)
+			return
+		}
+		c.extend().memoizeTTL = ttl
+	}
+}
+
 // Context allow to set context of retry
 // default are Background context
 //
@@ -232,8 +769,16 @@ func RetryIf(retryIf RetryIfFunc) Option {
 //		},
 //		retry.Context(ctx),
 //	)
+//
+// When the context is cancelled with a cause (context.WithCancelCause, go1.20+),
+// the cause is returned instead of the generic context error.
 func Context(ctx context.Context) Option {
 	return func(c *Config) {
+		c.describe("Context", ctx)
+		if ctx == nil {
+			c.invalid("Context must not be nil")
+			return
+		}
 		c.context = ctx
 	}
 }
@@ -257,10 +802,39 @@ func Context(ctx context.Context) Option {
 //	)
 func WithTimer(t Timer) Option {
 	return func(c *Config) {
+		c.describe("WithTimer", t)
+		if t == nil {
+			c.invalid("WithTimer must not be nil")
+			return
+		}
 		c.timer = t
 	}
 }
 
+// WithClock provides a way to swap out the source of time, e.g. for a mock clock in tests.
+// The clock is used for sleeping too (it replaces the Timer),
+// so all time-dependent features are driven by it.
+//
+// Both `clock.New()` and `*clock.Mock` of [benbjohnson/clock](https://github.com/benbjohnson/clock)
+// satisfy Clock, so test suites using that library can advance virtual time through retries
+// (see [example](examples/clock_mock_test.go)).
+//
+//	retry.Do(
+//		func() error { ... },
+//		retry.WithClock(mockClock),
+//	)
+func WithClock(clock Clock) Option {
+	return func(c *Config) {
+		c.describe("WithClock", clock)
+		if clock == nil {
+			c.invalid("WithClock must not be nil")
+			return
+		}
+		c.clock = clock
+		c.timer = clock
+	}
+}
+
 // WrapContextErrorWithLastError allows the context error to be returned wrapped with the last error that the
 // retried function returned. This is only applicable when Attempts is set to 0 to retry indefinitly and when
 // using a context to cancel / timeout
@@ -280,6 +854,58 @@ func WithTimer(t Timer) Option {
 //	)
 func WrapContextErrorWithLastError(wrapContextErrorWithLastError bool) Option {
 	return func(c *Config) {
+		c.describe("WrapContextErrorWithLastError", wrapContextErrorWithLastError)
 		c.wrapContextErrorWithLastError = wrapContextErrorWithLastError
 	}
 }
+
+// DeadlineAwareDelay caps every delay to the time remaining until the deadline of the context minus the margin.
+// When the delay would outlast the deadline, the retry wakes up `margin` before the deadline
+// and returns context.DeadlineExceeded (as if the context expired), instead of sleeping until the context expires,
+// so the caller gets the error early enough to handle it.
+// Time is measured by the Clock (see `WithClock`).
+// default is disabled
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+//	defer cancel()
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.Context(ctx),
+//		retry.DeadlineAwareDelay(10*time.Millisecond),
+//	)
+func DeadlineAwareDelay(margin time.Duration) Option {
+	return func(c *Config) {
+		c.describe("DeadlineAwareDelay", margin)
+		if margin < 0 {
+			c.invalid("DeadlineAwareDelay margin must not be negative, got %v", margin)
+			return
+		}
+		c.extend().deadlineAware = true
+		c.extend().deadlineMargin = margin
+	}
+}
+
+// ErrorHistory sets count of the most recent errors recorded when Attempts is set to 0 to retry indefinitely.
+// When the retry is cancelled via context, the returned `retry.Error` contains the recorded errors
+// followed by the context error, so the cancellation still reports what kept failing.
+// Ignored when LastErrorOnly is set.
+//
+// default is 0 (no history, only the context error is returned)
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.Context(ctx),
+//		retry.Attempts(0),
+//		retry.ErrorHistory(10),
+//	)
+func ErrorHistory(errorHistory uint) Option {
+	return func(c *Config) {
+		c.describe("ErrorHistory", errorHistory)
+		c.extend().errorHistory = errorHistory
+	}
+}
@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Pacer is a leaky bucket spacing attempts at a constant rate.
+// Sharing one Pacer by many retries smooths their traffic: when many operations back off
+// and wake up at the same time, their attempts leave the bucket one by one instead of as a burst.
+// Pacer is safe for concurrent use.
+type Pacer struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time // the earliest time of the next attempt
+}
+
+// NewPacer returns a Pacer letting one attempt through every interval
+func NewPacer(interval time.Duration) *Pacer {
+	return &Pacer{interval: interval}
+}
+
+// reserve takes the next free slot of the bucket, returns how long to wait for it
+func (p *Pacer) reserve(now time.Time) time.Duration {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.next.Before(now) {
+		p.next = now
+	}
+	wait := p.next.Sub(now)
+	p.next = p.next.Add(p.interval)
+	return wait
+}
+
+// WithPacer waits for a slot of the pacer before every attempt (including the first one),
+// so all retries sharing it don't make more than one attempt per interval of the pacer together.
+// The wait is on top of the delay between attempts and does not hold the semaphore of `WithSemaphore`.
+// When the context is done while waiting, the attempt fails with the context error.
+//
+//	var pacer = retry.NewPacer(100 * time.Millisecond)
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.WithPacer(pacer),
+//	)
+func WithPacer(pacer *Pacer) Option {
+	return func(c *Config) {
+		c.describe("WithPacer", pacerInterval(pacer))
+		if pacer == nil {
+			c.invalid("WithPacer must not be nil")
+			return
+		}
+		if pacer.interval <= 0 {
+			c.invalid("WithPacer interval must be positive, got %v", pacer.interval)
+			return
+		}
+		c.extend().pacer = pacer
+	}
+}
+
+func pacerInterval(pacer *Pacer) time.Duration {
+	if pacer == nil {
+		return 0
+	}
+	return pacer.interval
+}
+
+// waitForPacer waits for a slot of the pacer (if any), returns false when the context is done in the meantime
+func (c *Config) waitForPacer() bool {
+	if c.ext.pacer == nil {
+		return true
+	}
+	wait := c.ext.pacer.reserve(c.clock.Now())
+	if wait <= 0 {
+		return c.context.Err() == nil
+	}
+	select {
+	case <-c.timer.After(wait):
+		return true
+	case <-c.context.Done():
+		return false
+	}
+}
@@ -0,0 +1,56 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPacer(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC)}
+	pacer := NewPacer(time.Second)
+
+	// a burst of attempts leaves the bucket one per second
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, Do(func() error { return nil }, WithPacer(pacer), WithClock(clock)))
+	}
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, clock.delays)
+
+	// the delay between attempts counts into the pacing
+	clock.delays = nil
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(2),
+		Delay(3*time.Second),
+		DelayType(FixedDelay),
+		WithPacer(pacer),
+		WithClock(clock),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []time.Duration{time.Second, 3 * time.Second}, clock.delays)
+}
+
+func TestPacerContext(t *testing.T) {
+	pacer := NewPacer(time.Hour)
+	assert.NoError(t, Do(func() error { return nil }, WithPacer(pacer)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	var count int
+	err := Do(
+		func() error {
+			count++
+			return nil
+		},
+		WithPacer(pacer),
+		Context(ctx),
+	)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, count)
+
+	assert.ErrorIs(t, Validate(WithPacer(nil)), ErrInvalidOption)
+	assert.ErrorIs(t, Validate(WithPacer(NewPacer(0))), ErrInvalidOption)
+}
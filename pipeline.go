@@ -0,0 +1,61 @@
+package retry
+
+import "fmt"
+
+// Stage is a step of a Pipeline retried with its own options
+type Stage struct {
+	// Name of the stage, reported by StageError
+	Name string
+	// Func is the retried function of the stage
+	Func RetryableFunc
+	// Options of the retry of the stage
+	Options []Option
+	// RestartPipeline makes the failure of the stage (after its own retries) restart the whole pipeline
+	// from the first stage, e.g. when an upload fails because the token of the auth stage expired.
+	// Otherwise the failure of the stage is unrecoverable for the pipeline.
+	RestartPipeline bool
+}
+
+// StageError is the error of a failed stage of a Pipeline
+type StageError struct {
+	// Stage is the name of the failed stage
+	Stage string
+	// Err is the error returned by the retry of the stage
+	Err error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("stage %s: %s", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// Pipeline composes stages run in order into a RetryableFunc.
+// Every stage is retried with its own options; when a stage fails, the pipeline fails with `*StageError`,
+// which is unrecoverable unless the stage has RestartPipeline set.
+// Retry the pipeline itself to restart it from the first stage:
+//
+//	err := retry.Do(
+//		retry.Pipeline(
+//			retry.Stage{Name: "auth", Func: auth},
+//			retry.Stage{Name: "upload", Func: upload, Options: []retry.Option{retry.Attempts(5)}, RestartPipeline: true},
+//			retry.Stage{Name: "commit", Func: commit},
+//		),
+//		retry.Attempts(3),
+//	)
+func Pipeline(stages ...Stage) RetryableFunc {
+	return func() error {
+		for _, stage := range stages {
+			if err := Do(stage.Func, stage.Options...); err != nil {
+				stageErr := &StageError{Stage: stage.Name, Err: err}
+				if stage.RestartPipeline {
+					return stageErr
+				}
+				return Unrecoverable(stageErr)
+			}
+		}
+		return nil
+	}
+}
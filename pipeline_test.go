@@ -0,0 +1,54 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	var log []string
+	stage := func(name string, failures int) RetryableFunc {
+		return func() error {
+			log = append(log, name)
+			if failures > 0 {
+				failures--
+				return errors.New(name + " failed")
+			}
+			return nil
+		}
+	}
+
+	err := Do(Pipeline(
+		Stage{Name: "auth", Func: stage("auth", 0)},
+		Stage{Name: "upload", Func: stage("upload", 3), Options: []Option{Attempts(2), Delay(0)}, RestartPipeline: true},
+		Stage{Name: "commit", Func: stage("commit", 1), Options: []Option{Delay(0)}},
+	), Delay(0))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"auth", "upload", "upload",
+		"auth", "upload", "upload",
+		"commit", "commit",
+	}, log)
+}
+
+func TestPipelineUnrecoverableStage(t *testing.T) {
+	var log []string
+	err := Do(Pipeline(
+		Stage{Name: "auth", Func: func() error {
+			log = append(log, "auth")
+			return nil
+		}},
+		Stage{Name: "commit", Func: func() error {
+			log = append(log, "commit")
+			return errors.New("conflict")
+		}, Options: []Option{Attempts(2), Delay(0)}},
+	), Delay(0))
+
+	var stageErr *StageError
+	assert.ErrorAs(t, err, &stageErr)
+	assert.Equal(t, "commit", stageErr.Stage)
+	assert.Len(t, stageErr.Err.(Error), 2)
+	assert.Equal(t, []string{"auth", "commit", "commit"}, log)
+}
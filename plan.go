@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrInfinitePlan is returned by `Plan` when Attempts is set to 0 (retry until success)
+var ErrInfinitePlan = errors.New("retry: cannot plan infinite retries")
+
+// maxPlanCapacity limits the delays allocated by Plan up front, longer plans grow as they are evaluated
+const maxPlanCapacity = 64
+
+// Plan returns the delays the configured options produce between attempts, without executing anything,
+// so the exact delay schedule can be unit-tested and documented.
+// Delays are evaluated with a nil error; random delays (e.g. RandomDelay) are sampled once.
+// The plan ends early at a StopDelay. A panic of the DelayType is returned as an unrecoverable `HookPanicError`.
+//
+//	delays, _ := retry.Plan(
+//		retry.Attempts(4),
+//		retry.Delay(100*time.Millisecond),
+//		retry.DelayType(retry.BackOffDelay),
+//	)
+//	// [100ms 200ms 400ms]
+func Plan(opts ...Option) ([]time.Duration, error) {
+	config := newDefaultRetryConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.ext.err != nil {
+		return nil, config.ext.err
+	}
+
+	if config.attempts == 0 {
+		return nil, ErrInfinitePlan
+	}
+
+	capacity := config.attempts - 1
+	if capacity > maxPlanCapacity {
+		capacity = maxPlanCapacity
+	}
+	delays := make([]time.Duration, 0, capacity)
+	err := containHookPanic(func() {
+		for n := config.ext.startAttempt; n+1 < config.attempts; n++ {
+			delayTime := delay(config, n, nil)
+			if delayTime == StopDelay {
+				break
+			}
+			if config.ext.immediateFirstRetry && n == config.ext.startAttempt {
+				delayTime = 0
+			}
+			delays = append(delays, delayTime)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delays, nil
+}
@@ -0,0 +1,53 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlan(t *testing.T) {
+	delays, err := Plan(
+		Attempts(5),
+		Delay(100*time.Millisecond),
+		MaxDelay(time.Second/2),
+		DelayType(BackOffDelay),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{
+		100 * time.Millisecond,
+		200 * time.Millisecond,
+		400 * time.Millisecond,
+		500 * time.Millisecond,
+	}, delays)
+
+	delays, err = Plan(
+		Attempts(3),
+		Delay(time.Second),
+		DelayType(FixedDelay),
+		ImmediateFirstRetry(true),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{0, time.Second}, delays)
+
+	delays, err = Plan(Attempts(1))
+	assert.NoError(t, err)
+	assert.Empty(t, delays)
+
+	_, err = Plan(Attempts(0))
+	assert.ErrorIs(t, err, ErrInfinitePlan)
+
+	delays, err = Plan(
+		Attempts(1<<31),
+		DelayType(func(n uint, _ error, _ *Config) time.Duration {
+			if n == 3 {
+				return StopDelay
+			}
+			return time.Second
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Len(t, delays, 3)
+	assert.LessOrEqual(t, cap(delays), maxPlanCapacity, "huge Attempts don't allocate all delays up front")
+}
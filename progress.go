@@ -0,0 +1,75 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrNoProgress is reported when the retry is aborted by `AbortIfNoProgress`
+var ErrNoProgress = errors.New("retry: no progress reported")
+
+type progressKey struct{}
+
+// progressTracker holds the time of the last reported progress in unix nanoseconds
+type progressTracker struct {
+	last  int64
+	clock Clock
+}
+
+func (p *progressTracker) report() {
+	atomic.StoreInt64(&p.last, p.clock.Now().UnixNano())
+}
+
+// stalled returns true when no progress was reported for longer than timeout
+func (p *progressTracker) stalled(timeout time.Duration) bool {
+	if p == nil || timeout <= 0 {
+		return false
+	}
+	return p.clock.Now().Sub(time.Unix(0, atomic.LoadInt64(&p.last))) > timeout
+}
+
+// ReportProgress reports that the operation retried by `DoContext` made progress
+// (e.g. received some data) even if the attempt fails at the end.
+// It does nothing for contexts not coming from the retry.
+func ReportProgress(ctx context.Context) {
+	if p, ok := ctx.Value(progressKey{}).(*progressTracker); ok {
+		p.report()
+	}
+}
+
+// AbortIfNoProgress stops retrying when no progress was reported by `ReportProgress`
+// for longer than the given duration (checked after each failed attempt),
+// so an operation which is fundamentally stuck isn't retried endlessly.
+// The error returned contains `ErrNoProgress`.
+//
+// Without `DoContext` nothing can report progress and the duration is counted from the first attempt.
+//
+//	retry.DoContext(
+//		ctx,
+//		func(ctx context.Context) error {
+//			for chunk := range download(ctx) {
+//				retry.ReportProgress(ctx)
+//				...
+//			}
+//		},
+//		retry.Attempts(0),
+//		retry.AbortIfNoProgress(time.Minute),
+//	)
+func AbortIfNoProgress(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.describe("AbortIfNoProgress", timeout)
+		if timeout < 0 {
+			c.invalid("AbortIfNoProgress timeout must not be negative, got %v", timeout)
+			return
+		}
+		c.extend().noProgressTimeout = timeout
+	}
+}
+
+func withProgressTracker(p *progressTracker) Option {
+	return func(c *Config) {
+		c.progress = p
+	}
+}
@@ -0,0 +1,78 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAbortIfNoProgress(t *testing.T) {
+	testErr := errors.New("test")
+	attempts := 0
+	err := DoContext(
+		context.Background(),
+		func(ctx context.Context) error {
+			attempts++
+			if attempts < 5 {
+				ReportProgress(ctx)
+			}
+			time.Sleep(5 * time.Millisecond)
+			return testErr
+		},
+		Attempts(0),
+		Delay(time.Nanosecond),
+		DelayType(FixedDelay),
+		AbortIfNoProgress(20*time.Millisecond),
+	)
+	assert.ErrorIs(t, err, ErrNoProgress)
+	assert.ErrorIs(t, err, testErr)
+	assert.GreaterOrEqual(t, attempts, 6)
+	assert.Less(t, attempts, 15)
+
+	attempts = 0
+	err = Do(
+		func() error {
+			attempts++
+			time.Sleep(5 * time.Millisecond)
+			return testErr
+		},
+		Attempts(100),
+		Delay(time.Nanosecond),
+		DelayType(FixedDelay),
+		AbortIfNoProgress(12*time.Millisecond),
+	)
+	assert.ErrorIs(t, err, ErrNoProgress)
+	assert.Less(t, attempts, 10)
+}
+
+func TestDoContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	type key struct{}
+	ctx = context.WithValue(ctx, key{}, "value")
+
+	attempts := 0
+	err := DoContext(ctx, func(ctx context.Context) error {
+		attempts++
+		assert.Equal(t, "value", ctx.Value(key{}))
+		if attempts == 2 {
+			cancel()
+		}
+		return errors.New("test")
+	}, Delay(time.Nanosecond))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, attempts)
+
+	v, err := DoWithDataContext(context.Background(), func(ctx context.Context) (int, error) {
+		ReportProgress(ctx)
+		return 42, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+
+	ReportProgress(context.Background())
+}
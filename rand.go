@@ -0,0 +1,45 @@
+package retry
+
+import "math/rand"
+
+// randomGenerator draws the random numbers of a retry (jitter, randomization, fault injection)
+type randomGenerator interface {
+	Float64() float64
+	Int63n(n int64) int64
+}
+
+// WithRandSource draws the random numbers of the retry (jitter, RandomizationFactor, ProportionalJitter,
+// WithFaultInjection) from the given source instead of the default generator, e.g. a seeded source for reproducible delays.
+// A source shared by concurrent retries must be safe for concurrent use.
+//
+//	retry.Do(
+//		func() error { ... },
+//		retry.WithRandSource(rand.NewSource(42)),
+//	)
+func WithRandSource(src rand.Source) Option {
+	return func(c *Config) {
+		c.describe("WithRandSource", src)
+		if src == nil {
+			c.invalid("WithRandSource must not be nil")
+			return
+		}
+		c.rand = rand.New(src)
+		c.randInjected = true
+	}
+}
+
+// random returns the random generator of the retry, the default one is acquired on first use
+func (c *Config) random() randomGenerator {
+	if c.rand == nil {
+		c.rand = acquireRandom()
+	}
+	return c.rand
+}
+
+// releaseRandom releases the default random generator of the finished retry
+func (c *Config) releaseRandom() {
+	if c.rand != nil && !c.randInjected {
+		releaseRandom(c.rand)
+		c.rand = nil
+	}
+}
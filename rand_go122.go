@@ -0,0 +1,23 @@
+//go:build go1.22
+
+package retry
+
+import "math/rand/v2"
+
+// globalRandom draws from the global ChaCha8 generator of math/rand/v2,
+// which is lock-free and needs no generator per retry
+type globalRandom struct{}
+
+func (globalRandom) Float64() float64 {
+	return rand.Float64()
+}
+
+func (globalRandom) Int63n(n int64) int64 {
+	return rand.Int64N(n)
+}
+
+func acquireRandom() randomGenerator {
+	return globalRandom{}
+}
+
+func releaseRandom(randomGenerator) {}
@@ -0,0 +1,24 @@
+//go:build !go1.22
+
+package retry
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// randPool holds the random generators of retries, so concurrent retries don't contend
+// for the lock of the global math/rand source and no generator is allocated per retry
+var randPool = sync.Pool{
+	New: func() interface{} {
+		return rand.New(rand.NewSource(rand.Int63()))
+	},
+}
+
+func acquireRandom() randomGenerator {
+	return randPool.Get().(*rand.Rand)
+}
+
+func releaseRandom(r randomGenerator) {
+	randPool.Put(r)
+}
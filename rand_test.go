@@ -0,0 +1,55 @@
+package retry
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandom(t *testing.T) {
+	config := newRetryConfig([]Option{MaxJitter(time.Second)})
+	assert.Nil(t, config.rand, "acquired on first use")
+
+	jitter := RandomDelay(0, nil, config)
+	assert.True(t, jitter >= 0 && jitter < time.Second)
+	assert.NotNil(t, config.rand)
+
+	config.releaseRandom()
+	assert.Nil(t, config.rand)
+}
+
+func TestWithRandSource(t *testing.T) {
+	jitters := func() []time.Duration {
+		var delays []time.Duration
+		_ = Do(func() error { return errors.New("test") },
+			Attempts(4),
+			DelayType(RandomDelay),
+			MaxJitter(time.Millisecond),
+			OnDelay(func(n uint, d time.Duration) { delays = append(delays, d) }),
+			WithRandSource(rand.NewSource(42)),
+		)
+		return delays
+	}
+	first := jitters()
+	assert.Len(t, first, 3)
+	assert.Equal(t, first, jitters(), "seeded source gives the same delays")
+
+	assert.ErrorIs(t, Validate(WithRandSource(nil)), ErrInvalidOption)
+}
+
+func TestRandomConcurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = Do(func() error { return errors.New("test") },
+				Attempts(3), Delay(time.Microsecond), MaxJitter(time.Microsecond), ProportionalJitter(10))
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter exposes the delays of the options per item, compatible with the `RateLimiter` interface
+// of the workqueue of k8s.io/client-go, so controllers use the same backoff configuration
+// for inline retries and for requeues:
+//
+//	opts := []retry.Option{retry.Delay(time.Second), retry.MaxDelay(5 * time.Minute)}
+//
+//	queue := workqueue.NewRateLimitingQueue(retry.NewRateLimiter(opts...))
+//	...
+//	err := retry.Do(func() error { ... }, opts...)
+//
+// The n-th requeue of an item waits as the n-th retry of `Do` would (with a nil error).
+// Delays beyond the count of Attempts keep growing as if the attempts were unlimited.
+// When the DelayType returns StopDelay, the requeue waits MaxDelay (or the previous delay of the item without MaxDelay).
+type RateLimiter struct {
+	mu       sync.Mutex
+	config   *Config
+	requeues map[interface{}]requeues
+}
+
+// requeues of an item since Forget
+type requeues struct {
+	n    uint
+	last time.Duration // the last delay, repeated when the DelayType stops
+}
+
+// NewRateLimiter returns a RateLimiter delaying requeues by the options
+func NewRateLimiter(opts ...Option) *RateLimiter {
+	return &RateLimiter{
+		config:   newRetryConfig(opts),
+		requeues: make(map[interface{}]requeues),
+	}
+}
+
+// When returns the delay before the next requeue of the item and counts the requeue
+func (r *RateLimiter) When(item interface{}) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q := r.requeues[item]
+	d := delay(r.config, r.config.ext.startAttempt+q.n, nil)
+	switch {
+	case d == StopDelay:
+		// a requeue can't be refused, it waits the longest delay instead of spinning
+		if r.config.maxDelay > 0 {
+			d = r.config.maxDelay
+		} else {
+			d = q.last
+		}
+	case d < 0:
+		d = 0
+	}
+	r.requeues[item] = requeues{n: q.n + 1, last: d}
+	return d
+}
+
+// Forget clears the requeues of the item, e.g. after it was processed successfully
+func (r *RateLimiter) Forget(item interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.requeues, item)
+}
+
+// NumRequeues returns how many times the item was requeued since Forget
+func (r *RateLimiter) NumRequeues(item interface{}) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return int(r.requeues[item].n)
+}
+
+// TypedRateLimiter is RateLimiter compatible with the `TypedRateLimiter` interface of the workqueue
+// for queues of items of type T
+type TypedRateLimiter[T comparable] struct {
+	limiter *RateLimiter
+}
+
+// NewTypedRateLimiter returns a TypedRateLimiter delaying requeues by the options
+func NewTypedRateLimiter[T comparable](opts ...Option) *TypedRateLimiter[T] {
+	return &TypedRateLimiter[T]{limiter: NewRateLimiter(opts...)}
+}
+
+// When returns the delay before the next requeue of the item and counts the requeue
+func (r *TypedRateLimiter[T]) When(item T) time.Duration {
+	return r.limiter.When(item)
+}
+
+// Forget clears the requeues of the item, e.g. after it was processed successfully
+func (r *TypedRateLimiter[T]) Forget(item T) {
+	r.limiter.Forget(item)
+}
+
+// NumRequeues returns how many times the item was requeued since Forget
+func (r *TypedRateLimiter[T]) NumRequeues(item T) int {
+	return r.limiter.NumRequeues(item)
+}
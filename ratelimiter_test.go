@@ -0,0 +1,66 @@
+package retry
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter(t *testing.T) {
+	opts := []Option{
+		Delay(10 * time.Millisecond),
+		MaxDelay(50 * time.Millisecond),
+		DelayType(BackOffDelay),
+	}
+	limiter := NewRateLimiter(opts...)
+
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		delays = append(delays, limiter.When("a"))
+	}
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 40 * time.Millisecond, 50 * time.Millisecond}, delays)
+	assert.Equal(t, 4, limiter.NumRequeues("a"))
+
+	plan, _ := Plan(append(opts, Attempts(4))...)
+	assert.Equal(t, plan, delays[:3], "requeues wait as retries do")
+
+	assert.Equal(t, 10*time.Millisecond, limiter.When("b"), "items are counted separately")
+
+	limiter.Forget("a")
+	assert.Equal(t, 0, limiter.NumRequeues("a"))
+	assert.Equal(t, 10*time.Millisecond, limiter.When("a"))
+}
+
+func TestRateLimiterStopDelay(t *testing.T) {
+	stopAfter2 := DelayType(func(n uint, err error, config *Config) time.Duration {
+		if n >= 2 {
+			return StopDelay
+		}
+		return 10 * time.Millisecond * time.Duration(n+1)
+	})
+
+	limiter := NewRateLimiter(stopAfter2)
+	var delays []time.Duration
+	for i := 0; i < 4; i++ {
+		delays = append(delays, limiter.When("a"))
+	}
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond, 20 * time.Millisecond}, delays, "the last delay is repeated")
+
+	limiter = NewRateLimiter(stopAfter2, MaxDelay(time.Minute))
+	limiter.When("a")
+	limiter.When("a")
+	assert.Equal(t, time.Minute, limiter.When("a"), "MaxDelay after StopDelay")
+}
+
+func TestTypedRateLimiter(t *testing.T) {
+	type key struct{ namespace, name string }
+	limiter := NewTypedRateLimiter[key](Delay(10*time.Millisecond), DelayType(BackOffDelay))
+
+	item := key{"default", "web"}
+	assert.Equal(t, 10*time.Millisecond, limiter.When(item))
+	assert.Equal(t, 20*time.Millisecond, limiter.When(item))
+	assert.Equal(t, 2, limiter.NumRequeues(item))
+	limiter.Forget(item)
+	assert.Equal(t, 0, limiter.NumRequeues(item))
+}
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Attempt is a record of a single attempt of the retried function
+type Attempt struct {
+	// Number of the attempt, starting from 1
+	Number uint
+	// Start is the time the attempt started at
+	Start time.Time
+	// Duration of the attempt
+	Duration time.Duration
+	// Err returned by the attempt, nil for a successful one
+	Err error
+	// Delay chosen after the attempt, zero if there was none
+	Delay time.Duration
+}
+
+// Recorder captures every attempt of the retry for inspection in tests or debugging endpoints.
+// It may be shared by several retries, also concurrent ones, Reset clears the records.
+type Recorder struct {
+	mu       sync.Mutex
+	attempts []Attempt
+	retries  []uint64 // the retry of each attempt, delays are attached to the attempts of their retry
+	lastID   uint64   // the id of the last retry recorded
+}
+
+// Attempts returns a copy of recorded attempts
+func (r *Recorder) Attempts() []Attempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Attempt(nil), r.attempts...)
+}
+
+// Reset clears recorded attempts
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = nil
+	r.retries = nil
+}
+
+// record records the attempt just executed by the retry c
+func (r *Recorder) record(c *Config, start, end time.Time, err error) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c.ext.recorderRetry == 0 {
+		r.lastID++
+		c.ext.recorderRetry = r.lastID
+	}
+	r.retries = append(r.retries, c.ext.recorderRetry)
+	r.attempts = append(r.attempts, Attempt{
+		Number:   c.attempted,
+		Start:    start,
+		Duration: end.Sub(start),
+		Err:      err,
+	})
+}
+
+// recordDelay sets the delay of the last attempt of the retry c
+func (r *Recorder) recordDelay(c *Config, d time.Duration) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if c.ext.recorderRetry == 0 {
+		return
+	}
+	for i := len(r.retries) - 1; i >= 0; i-- {
+		if r.retries[i] == c.ext.recorderRetry {
+			r.attempts[i].Delay = d
+			return
+		}
+	}
+}
+
+// WithRecorder records every attempt (error, timing and the delay chosen after it) into the recorder
+//
+//	recorder := &retry.Recorder{}
+//	err := retry.Do(
+//		func() error { ... },
+//		retry.WithRecorder(recorder),
+//	)
+//	for _, attempt := range recorder.Attempts() {
+//		log.Printf("#%d took %s: %v, waited %s", attempt.Number, attempt.Duration, attempt.Err, attempt.Delay)
+//	}
+func WithRecorder(recorder *Recorder) Option {
+	return func(c *Config) {
+		c.describe("WithRecorder")
+		c.extend().recorder = recorder
+	}
+}
@@ -0,0 +1,83 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithRecorder(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC)}
+	recorder := &Recorder{}
+	testErr := errors.New("test")
+
+	attempts := 0
+	err := Do(
+		func() error {
+			attempts++
+			clock.now = clock.now.Add(time.Second)
+			if attempts < 3 {
+				return testErr
+			}
+			return nil
+		},
+		Delay(time.Minute),
+		DelayType(BackOffDelay),
+		WithClock(clock),
+		WithRecorder(recorder),
+	)
+	assert.NoError(t, err)
+
+	records := recorder.Attempts()
+	assert.Len(t, records, 3)
+	assert.Equal(t, Attempt{
+		Number:   1,
+		Start:    time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC),
+		Duration: time.Second,
+		Err:      testErr,
+		Delay:    time.Minute,
+	}, records[0])
+	assert.Equal(t, uint(2), records[1].Number)
+	assert.Equal(t, 2*time.Minute, records[1].Delay)
+	assert.Equal(t, time.Date(2024, time.March, 6, 8, 3, 2, 0, time.UTC), records[2].Start)
+	assert.NoError(t, records[2].Err)
+	assert.Equal(t, time.Duration(0), records[2].Delay)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Attempts())
+}
+
+func TestRecorderSharedByConcurrentRetries(t *testing.T) {
+	recorder := &Recorder{}
+	testErr := errors.New("test")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = Do(
+				func() error { return testErr },
+				Attempts(3),
+				Delay(time.Millisecond),
+				DelayType(FixedDelay),
+				WithRecorder(recorder),
+			)
+		}()
+	}
+	wg.Wait()
+
+	counts := map[uint]int{}
+	for _, attempt := range recorder.Attempts() {
+		counts[attempt.Number]++
+		if attempt.Number < 3 {
+			assert.Equal(t, time.Millisecond, attempt.Delay, "attempt #%d", attempt.Number)
+		} else {
+			assert.Equal(t, time.Duration(0), attempt.Delay, "the last attempt has no delay")
+		}
+	}
+	assert.Equal(t, map[uint]int{1: 4, 2: 4, 3: 4}, counts)
+}
@@ -0,0 +1,86 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Recording holds random jitter values picked during a retry, by RandomDelay, RandomizationFactor and ProportionalJitter.
+// It can be serialized (e.g. as JSON) and attached to a bug report,
+// then replayed by `WithReplay` to reproduce the exact timing of the retry sequence.
+type Recording struct {
+	mu      sync.Mutex
+	Jitters []time.Duration `json:"jitters"`
+}
+
+func (r *Recording) record(jitter time.Duration) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Jitters = append(r.Jitters, jitter)
+}
+
+func (r *Recording) replay(i int) (time.Duration, bool) {
+	if r == nil {
+		return 0, false
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if i >= len(r.Jitters) {
+		return 0, false
+	}
+	return r.Jitters[i], true
+}
+
+// RecordJitter appends random jitter values picked by RandomDelay (the delays randomized by RandomizationFactor
+// and the jitter of ProportionalJitter) to the recording
+//
+//	recording := &retry.Recording{}
+//	err := retry.Do(
+//		func() error { ... },
+//		retry.RecordJitter(recording),
+//	)
+//	if err != nil {
+//		b, _ := json.Marshal(recording)
+//		log.Printf("retry failed, jitter: %s", b)
+//	}
+func RecordJitter(recording *Recording) Option {
+	return func(c *Config) {
+		c.describe("RecordJitter")
+		c.extend().jitterRecording = recording
+	}
+}
+
+// WithReplay makes RandomDelay (RandomizationFactor and ProportionalJitter) return jitter values of the recording in order,
+// from the beginning, instead of random ones. When the recording is exhausted, random values are picked again.
+//
+//	var recording retry.Recording
+//	_ = json.Unmarshal(fromBugReport, &recording)
+//	err := retry.Do(
+//		func() error { ... },
+//		retry.WithReplay(&recording),
+//	)
+func WithReplay(recording *Recording) Option {
+	return func(c *Config) {
+		c.describe("WithReplay")
+		c.extend().jitterReplay = recording
+	}
+}
+
+// jitter returns the next value of the replayed recording, or else the value picked by pick, which is recorded
+func (c *Config) jitter(pick func() time.Duration) time.Duration {
+	ext := c.extension()
+	pos := c.jitterPos
+	c.jitterPos++
+	if jitter, ok := ext.jitterReplay.replay(pos); ok {
+		return jitter
+	}
+
+	jitter := pick()
+	ext.jitterRecording.record(jitter)
+	return jitter
+}
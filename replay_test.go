@@ -0,0 +1,85 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordAndReplayJitter(t *testing.T) {
+	recording := &Recording{}
+	timer := &recordingTimer{}
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(4),
+		Delay(time.Millisecond),
+		MaxJitter(time.Second),
+		WithTimer(timer),
+		RecordJitter(recording),
+	)
+	assert.Error(t, err)
+	assert.Len(t, recording.Jitters, 3)
+
+	b, err := json.Marshal(recording)
+	assert.NoError(t, err)
+	var replayed Recording
+	assert.NoError(t, json.Unmarshal(b, &replayed))
+
+	replayTimer := &recordingTimer{}
+	err = Do(
+		func() error { return errors.New("test") },
+		Attempts(4),
+		Delay(time.Millisecond),
+		MaxJitter(time.Second),
+		WithTimer(replayTimer),
+		WithReplay(&replayed),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, timer.delays, replayTimer.delays)
+
+	exhausted := &Recording{Jitters: []time.Duration{time.Hour}}
+	replayTimer.delays = nil
+	err = Do(
+		func() error { return errors.New("test") },
+		Attempts(3),
+		DelayType(RandomDelay),
+		MaxJitter(time.Millisecond),
+		WithTimer(replayTimer),
+		WithReplay(exhausted),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, time.Hour, replayTimer.delays[0])
+	assert.Less(t, replayTimer.delays[1], time.Millisecond)
+}
+
+func TestRecordAndReplayRandomizationFactor(t *testing.T) {
+	recording := &Recording{}
+	timer := &recordingTimer{}
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(4),
+		Delay(time.Second),
+		DelayType(BackOffDelay),
+		RandomizationFactor(0.5),
+		WithTimer(timer),
+		RecordJitter(recording),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, timer.delays, recording.Jitters, "the randomized delays are recorded")
+
+	replayTimer := &recordingTimer{}
+	err = Do(
+		func() error { return errors.New("test") },
+		Attempts(4),
+		Delay(time.Second),
+		DelayType(BackOffDelay),
+		RandomizationFactor(0.5),
+		WithTimer(replayTimer),
+		WithReplay(recording),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, timer.delays, replayTimer.delays)
+}
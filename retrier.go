@@ -0,0 +1,129 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Retrier is a reusable retry policy which keeps backoff state between calls.
+//
+// Consecutive failing calls continue the backoff schedule where the previous call stopped,
+// so a long-lived worker calling `Do` repeatedly doesn't hammer a failing dependency
+// with the short initial delays again and again. A successful call resets the state.
+// See `StatefulBackOff` for backing off between calls as well.
+//
+//	r := retry.NewRetrier(retry.Attempts(3), retry.Delay(time.Second))
+//	for {
+//		err := r.Do(poll)
+//		...
+//	}
+//
+// Retrier is safe for concurrent use.
+type Retrier struct {
+	opts []Option
+
+	mu sync.Mutex
+	n  uint // count of failed attempts since the last success or Reset
+
+	memo      any       // value of the last successful call, with Memoize
+	memoValid bool      // memo is set, it may be nil (e.g. for Do)
+	memoUntil time.Time // when the memo expires
+}
+
+// NewRetrier creates a Retrier with options applied to every call
+func NewRetrier(opts ...Option) *Retrier {
+	return &Retrier{opts: opts}
+}
+
+// Do is `retry.Do` with the Retrier options and backoff state.
+// Additional opts are applied after the Retrier options.
+func (r *Retrier) Do(retryableFunc RetryableFunc, opts ...Option) error {
+	_, err := RetrierDoWithData(r, func() (any, error) {
+		return nil, retryableFunc()
+	}, opts...)
+	return err
+}
+
+// RetrierDoWithData is `retry.DoWithData` with the Retrier options and backoff state.
+// Additional opts are applied after the Retrier options.
+func RetrierDoWithData[T any](r *Retrier, retryableFunc RetryableFuncWithData[T], opts ...Option) (T, error) {
+	var attempts uint
+	countedFunc := func() (T, error) {
+		attempts++
+		return retryableFunc()
+	}
+
+	r.mu.Lock()
+	n := r.n
+	r.mu.Unlock()
+
+	allOpts := make([]Option, 0, len(r.opts)+len(opts)+1)
+	allOpts = append(allOpts, r.opts...)
+	allOpts = append(allOpts, opts...)
+	allOpts = append(allOpts, delayOffset(n))
+
+	config := newRetryConfig(allOpts)
+	if config.ext.err != nil {
+		var emptyT T
+		return emptyT, config.ext.err
+	}
+
+	if config.ext.memoizeTTL > 0 {
+		r.mu.Lock()
+		// a nil memo is the zero value of T, e.g. of an interface type
+		memo, ok := r.memo.(T)
+		ok = (ok || r.memo == nil) && r.memoValid && config.clock.Now().Before(r.memoUntil)
+		r.mu.Unlock()
+		if ok {
+			return memo, nil
+		}
+	}
+
+	// wait before the first attempt when previous calls failed
+	if config.ext.statefulBackOff && n > 0 {
+		config.ext.delayOffset = n - 1
+		var d time.Duration
+		if err := containHookPanic(func() { d = delay(config, 0, nil) }); err != nil {
+			var emptyT T
+			return emptyT, err
+		}
+		if !config.sleep(d) {
+			var emptyT T
+			return emptyT, config.contextErr()
+		}
+		config.ext.delayOffset = n
+	}
+
+	t, err := do[T](config, callerFuncWithData[T](countedFunc))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil && config.ext.memoizeTTL > 0 {
+		r.memo, r.memoValid, r.memoUntil = t, true, config.clock.Now().Add(config.ext.memoizeTTL)
+	}
+	switch {
+	case err != nil:
+		r.n += attempts
+	case config.ext.statefulBackOff:
+		r.n /= 2
+	default:
+		r.n = 0
+	}
+
+	return t, err
+}
+
+// Reset clears the accumulated backoff state and the memoized value,
+// e.g. after an operator fixed the dependency, so the next call starts with the initial delay.
+func (r *Retrier) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.n = 0
+	r.memo, r.memoValid, r.memoUntil = nil, false, time.Time{}
+}
+
+func delayOffset(offset uint) Option {
+	return func(c *Config) {
+		c.extend().delayOffset = offset
+	}
+}
@@ -0,0 +1,159 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingTimer struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+func (t *recordingTimer) After(d time.Duration) <-chan time.Time {
+	t.mu.Lock()
+	t.delays = append(t.delays, d)
+	t.mu.Unlock()
+	return time.After(0)
+}
+
+func TestRetrierKeepsBackOffState(t *testing.T) {
+	timer := &recordingTimer{}
+	r := NewRetrier(
+		Attempts(2),
+		Delay(time.Millisecond),
+		DelayType(BackOffDelay),
+		WithTimer(timer),
+	)
+
+	testErr := errors.New("test")
+	assert.Error(t, r.Do(func() error { return testErr }))
+	assert.Error(t, r.Do(func() error { return testErr }))
+	assert.Equal(t, []time.Duration{time.Millisecond, 4 * time.Millisecond}, timer.delays)
+
+	assert.NoError(t, r.Do(func() error { return nil }))
+	timer.delays = nil
+	assert.Error(t, r.Do(func() error { return testErr }))
+	assert.Equal(t, []time.Duration{time.Millisecond}, timer.delays, "success resets state")
+}
+
+func TestRetrierReset(t *testing.T) {
+	timer := &recordingTimer{}
+	r := NewRetrier(
+		Attempts(2),
+		Delay(time.Millisecond),
+		DelayType(BackOffDelay),
+		WithTimer(timer),
+	)
+
+	testErr := errors.New("test")
+	assert.Error(t, r.Do(func() error { return testErr }))
+	r.Reset()
+	assert.Error(t, r.Do(func() error { return testErr }))
+	assert.Equal(t, []time.Duration{time.Millisecond, time.Millisecond}, timer.delays)
+}
+
+func TestRetrierDoWithData(t *testing.T) {
+	r := NewRetrier(Delay(time.Nanosecond))
+
+	attempts := 0
+	v, err := RetrierDoWithData(r, func() (int, error) {
+		attempts++
+		if attempts < 3 {
+			return 0, errors.New("test")
+		}
+		return 42, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetrierStatefulBackOff(t *testing.T) {
+	timer := &recordingTimer{}
+	r := NewRetrier(
+		Attempts(1),
+		Delay(time.Millisecond),
+		DelayType(BackOffDelay),
+		WithTimer(timer),
+		StatefulBackOff(true),
+	)
+
+	testErr := errors.New("test")
+	assert.Error(t, r.Do(func() error { return testErr }))
+	assert.Empty(t, timer.delays, "no wait before the first call")
+	assert.Error(t, r.Do(func() error { return testErr }))
+	assert.Error(t, r.Do(func() error { return testErr }))
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, timer.delays)
+
+	// 3 failures decay to 1 after success
+	timer.delays = nil
+	assert.NoError(t, r.Do(func() error { return nil }))
+	assert.NoError(t, r.Do(func() error { return nil }))
+	assert.NoError(t, r.Do(func() error { return nil }))
+	assert.Equal(t, []time.Duration{4 * time.Millisecond, time.Millisecond}, timer.delays)
+}
+
+func TestRetrierMemoize(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 8, 0, 0, 0, time.UTC)}
+	r := NewRetrier(Memoize(time.Minute), WithClock(clock))
+
+	var calls int
+	lookup := func() (int, error) {
+		calls++
+		return calls, nil
+	}
+
+	v, err := RetrierDoWithData(r, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	clock.now = clock.now.Add(30 * time.Second)
+	v, err = RetrierDoWithData(r, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v, "memoized within the ttl")
+
+	clock.now = clock.now.Add(time.Minute)
+	v, err = RetrierDoWithData(r, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, v, "executed after the ttl")
+
+	r.Reset()
+	v, err = RetrierDoWithData(r, lookup)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, v, "executed after Reset")
+
+	assert.ErrorIs(t, Validate(Memoize(-time.Second)), ErrInvalidOption)
+}
+
+func TestRetrierMemoizeDo(t *testing.T) {
+	r := NewRetrier(Memoize(time.Minute))
+
+	var calls int
+	poll := func() error {
+		calls++
+		return nil
+	}
+	assert.NoError(t, r.Do(poll))
+	assert.NoError(t, r.Do(poll))
+	assert.Equal(t, 1, calls, "memoized without data")
+
+	r.Reset()
+	assert.NoError(t, r.Do(poll))
+	assert.Equal(t, 2, calls, "executed after Reset")
+}
+
+func TestRetrierAppliesOptionsOnce(t *testing.T) {
+	var applied int
+	count := Option(func(c *Config) { applied++ })
+	r := NewRetrier(count, Attempts(1), StatefulBackOff(true), Delay(0))
+
+	assert.Error(t, r.Do(func() error { return errors.New("test") }))
+	assert.Equal(t, 1, applied)
+	assert.NoError(t, r.Do(func() error { return nil }, count))
+	assert.Equal(t, 3, applied)
+}
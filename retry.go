@@ -58,6 +58,12 @@ http get with retry with data:
 
 [next examples](https://github.com/avast/retry-go/tree/master/examples)
 
+# TRACING
+
+When [runtime/trace](https://pkg.go.dev/runtime/trace) is enabled, every retry is a "retry" task
+with a "retry.attempt" region per attempt and a "retry.sleep" region per delay,
+so `go tool trace` shows where the latency of a request went.
+
 # SEE ALSO
 
 * [giantswarm/retry-go](https://github.com/giantswarm/retry-go) - slightly complicated interface.
@@ -93,6 +99,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
+	"runtime/trace"
 	"strings"
 	"time"
 )
@@ -103,6 +111,12 @@ type RetryableFunc func() error
 // Function signature of retryable function with data
 type RetryableFuncWithData[T any] func() (T, error)
 
+// Function signature of retryable function accepting context of the retry
+type RetryableFuncContext func(ctx context.Context) error
+
+// Function signature of retryable function with data accepting context of the retry
+type RetryableFuncWithDataContext[T any] func(ctx context.Context) (T, error)
+
 // Default timer is a wrapper around time.After
 type timerImpl struct{}
 
@@ -110,21 +124,76 @@ func (t *timerImpl) After(d time.Duration) <-chan time.Time {
 	return time.After(d)
 }
 
+// Default clock is a wrapper around time.Now and time.After
+type clockImpl struct {
+	timerImpl
+}
+
+func (c *clockImpl) Now() time.Time {
+	return time.Now()
+}
+
+// caller is the retried function for the retry loop shared by Do and DoWithData
+// Named function types implement it without allocating a wrapping closure or boxing the result into any.
+type caller[T any] interface {
+	call() (T, error)
+}
+
+// callerFunc adapts RetryableFunc, the result is zero-sized
+type callerFunc RetryableFunc
+
+func (f callerFunc) call() (struct{}, error) {
+	return struct{}{}, f()
+}
+
+// callerFuncWithData adapts RetryableFuncWithData
+type callerFuncWithData[T any] RetryableFuncWithData[T]
+
+func (f callerFuncWithData[T]) call() (T, error) {
+	return f()
+}
+
 func Do(retryableFunc RetryableFunc, opts ...Option) error {
-	retryableFuncWithData := func() (any, error) {
-		// 执行 retryableFunc() 会返回 error
-		// 再封装一个 any, 连同 error 一起返回
-		return nil, retryableFunc()
-	}
+	_, err := do[struct{}](newRetryConfig(opts), callerFunc(retryableFunc))
+	return err
+}
 
-	_, err := DoWithData(retryableFuncWithData, opts...)
+// DoContext is `Do` with the retried function accepting the context of the retry.
+// The context is passed to every attempt and also set as the retry `Context`, so it overrides the option.
+// The context carries helpers like `ReportProgress` and `BudgetFromContext`.
+func DoContext(ctx context.Context, retryableFunc RetryableFuncContext, opts ...Option) error {
+	_, err := DoWithDataContext(ctx, func(ctx context.Context) (any, error) {
+		return nil, retryableFunc(ctx)
+	}, opts...)
 	return err
 }
 
+// DoWithDataContext is `DoWithData` with the retried function accepting the context of the retry.
+// See `DoContext`.
+func DoWithDataContext[T any](ctx context.Context, retryableFunc RetryableFuncWithDataContext[T], opts ...Option) (T, error) {
+	progress := &progressTracker{}
+	budget := &budgetTracker{}
+	attemptCtx := ctx
+	if ctx != nil { // nil ctx is reported by the Context option
+		attemptCtx = context.WithValue(ctx, progressKey{}, progress)
+		attemptCtx = context.WithValue(attemptCtx, budgetKey{}, budget)
+	}
+
+	ctxOpts := make([]Option, 0, len(opts)+3)
+	ctxOpts = append(ctxOpts, opts...)
+	ctxOpts = append(ctxOpts, Context(ctx), withProgressTracker(progress), withBudgetTracker(budget))
+
+	return DoWithData(func() (T, error) {
+		return retryableFunc(attemptCtx)
+	}, ctxOpts...)
+}
+
 func DoWithData[T any](retryableFunc RetryableFuncWithData[T], opts ...Option) (T, error) {
-	var n uint
-	var emptyT T
+	return do[T](newRetryConfig(opts), callerFuncWithData[T](retryableFunc))
+}
 
+// newRetryConfig returns the default config with opts applied
+func newRetryConfig(opts []Option) *Config {
 	// default
 	config := newDefaultRetryConfig()
 
@@ -132,39 +201,169 @@ func DoWithData[T any](retryableFunc RetryableFuncWithData[T], opts ...Option) (
 	for _, opt := range opts {
 		opt(config)
 	}
+	config.precomputeDelays()
+	if config.ext.asyncHooks {
+		config.makeHooksAsync()
+	}
+
+	return config
+}
+
+// do runs the retry loop with the config, shared by concurrent callers with WithSingleflight
+func do[T any, F caller[T]](config *Config, retryableFunc F) (T, error) {
+	if config.ext.err != nil {
+		var emptyT T
+		return emptyT, config.ext.err
+	}
+
+	if config.ext.singleflight != nil {
+		v, err, _ := config.ext.singleflight.Do(config.ext.singleflightKey, func() (interface{}, error) {
+			return run[T](config, retryableFunc)
+		})
+		t, _ := v.(T)
+		return t, err
+	}
+	return run[T](config, retryableFunc)
+}
+
+// run runs the retry loop and reports the result to the StatsCollector
+func run[T any, F caller[T]](config *Config, retryableFunc F) (T, error) {
+	slots, ok := config.enterBulkhead()
+	if !ok {
+		var emptyT T
+		return emptyT, ErrBulkheadFull
+	}
+	defer leaveBulkhead(slots)
+	defer config.releaseRandom()
+	if config.ext.signals != nil {
+		defer config.notifySignals()()
+	}
+
+	vars := loadExpvars()
+	vars.add(expvarActive, 1)
+
+	if trace.IsEnabled() {
+		var task *trace.Task
+		config.traceContext, task = trace.NewTask(config.context, "retry")
+		defer task.End()
+	}
+
+	config.attempted = config.ext.startAttempt
+	t, err := containHookPanics[T](config, retryableFunc)
+	if config.ext.stats != nil {
+		config.ext.stats.Finished(err == nil, config.attempted-config.ext.startAttempt)
+	}
+
+	vars.add(expvarActive, -1)
+	vars.add(expvarAttempts, int64(config.attempted-config.ext.startAttempt))
+	if config.exhausted {
+		vars.add(expvarExhausted, 1)
+	}
+	return t, err
+}
+
+// retryLoop is the retry loop
+func retryLoop[T any, F caller[T]](config *Config, retryableFunc F) (T, error) {
+	var emptyT T
+
+	if config.context.Err() != nil {
+		return emptyT, cancelError(config, nil)
+	}
+
+	// Attempts(1) 时不会重试, 执行一次直接返回
+	if config.attempts == 1 && config.singleAttempt() {
+		return attemptOnce[T](config, retryableFunc)
+	}
+
+	if config.ext.onProgress != nil {
+		config.startedAt = config.clock.Now()
+	}
 
-	if err := config.context.Err(); err != nil {
-		return emptyT, err
+	// 第一次执行前先等待 initialDelay
+	// 从保存的 State 恢复时, 从之前的次数继续, 并等到计划的时间再执行
+	n := config.ext.startAttempt
+	initialDelay := config.ext.initialDelay
+	if !config.ext.startAt.IsZero() {
+		initialDelay = config.ext.startAt.Sub(config.clock.Now())
+	}
+	if initialDelay > 0 || !config.ext.startAt.IsZero() {
+		if !config.sleep(initialDelay) {
+			return emptyT, cancelError(config, nil)
+		}
+	}
+
+	// 没有进展的计时从第一次执行开始
+	if config.ext.noProgressTimeout > 0 && config.progress == nil {
+		config.progress = &progressTracker{}
+	}
+	if config.progress != nil {
+		config.progress.clock = config.clock
+		config.progress.report()
 	}
 
 	// Setting attempts to 0 means we'll retry until we succeed
 	var lastErr error
+	var successes uint
 	if config.attempts == 0 {
+		var history Error // 最近的 errorHistory 个错误
 		for {
-			t, err := retryableFunc()
+			if !waitForWindow(config) {
+				return emptyT, abortError(config, history, lastErr)
+			}
+
+			t, err := attempt[T](config, retryableFunc)
 			if err == nil {
-				return t, nil
+				successes++
+				if successes >= config.ext.successThreshold {
+					return t, nil
+				}
+
+				if !config.sleepBetweenAttempts(delay(config, n, nil)) {
+					return emptyT, abortError(config, history, lastErr)
+				}
+				continue
 			}
+			successes = 0
 
-			if !IsRecoverable(err) {
+			if !IsRecoverable(err) || config.ext.unrecoverableIf(err) {
+				config.ext.onAbort(err, err, config.attempted)
 				return emptyT, err
 			}
 
-			if !config.retryIf(err) {
+			if !config.callRetryIf(err) {
 				return emptyT, err
 			}
 
 			lastErr = err
+			if config.ext.errorHistory > 0 && !config.lastErrorOnly {
+				if uint(len(history)) == config.ext.errorHistory {
+					copy(history, history[1:])
+					history = history[:len(history)-1]
+				}
+				history = append(history, Recoverable(err))
+			}
 
-			n++
-			config.onRetry(n, err)
-			select {
-			case <-config.timer.After(delay(config, n, err)):
-			case <-config.context.Done():
-				if config.wrapContextErrorWithLastError {
-					return emptyT, Error{config.context.Err(), lastErr}
+			if config.progress.stalled(config.ext.noProgressTimeout) {
+				config.ext.onAbort(ErrNoProgress, err, config.attempted)
+				if len(history) > 0 {
+					return emptyT, append(history, ErrNoProgress)
 				}
-				return emptyT, config.context.Err()
+				return emptyT, Error{ErrNoProgress, err}
+			}
+
+			n++
+			config.callOnRetry(n, err)
+			delayTime := delay(config, n, err)
+			if delayTime == StopDelay {
+				return emptyT, err
+			}
+			if config.ext.immediateFirstRetry && n == config.ext.startAttempt+1 {
+				delayTime = 0
+			}
+			config.reportProgress(n, 0, delayTime, err)
+			config.ext.onState(newState(config.clock.Now(), n, delayTime, err))
+			if !config.sleepBetweenAttempts(delayTime) {
+				return emptyT, abortError(config, history, lastErr)
 			}
 		}
 	}
@@ -173,58 +372,109 @@ func DoWithData[T any](retryableFunc RetryableFuncWithData[T], opts ...Option) (
 	errorLog := Error{} // 这是一个数组, 记录了所有的错误
 
 	// 因为后续会修改 attempts 值, 所以这里先拷贝一份, 后续使用拷贝的那一份
-	attemptsForError := make(map[error]uint, len(config.attemptsForError))
-	for err, attempts := range config.attemptsForError {
-		attemptsForError[err] = attempts
+	// 没有使用 AttemptsForError 时 map 为 nil, 不需要拷贝
+	var attemptsForError map[error]uint
+	if config.attemptsForError != nil {
+		attemptsForError = make(map[error]uint, len(config.attemptsForError))
+		for err, attempts := range config.attemptsForError {
+			attemptsForError[err] = attempts
+		}
 	}
 
+	var excluded uint   // 不计入总的 attempts 的次数
 	shouldRetry := true // 当超出重试次数时, 会退出循环
 	for shouldRetry {
+		// 不在允许的时间窗口内时, 等到窗口打开
+		if !waitForWindow(config) {
+			return emptyT, cancelError(config, errorLog)
+		}
+
 		// 执行用户传入的主流程函数, 我们要重试的就是他
-		t, err := retryableFunc()
+		t, err := attempt[T](config, retryableFunc)
 		// 如果执行成功了, 直接返回, 不需要再重试了
+		// 除非要求连续成功 successThreshold 次, 此时成功不消耗 attempts
 		if err == nil {
-			return t, nil
+			successes++
+			if successes >= config.ext.successThreshold {
+				return t, nil
+			}
+
+			if !config.sleepBetweenAttempts(delay(config, n, nil)) {
+				return emptyT, cancelError(config, errorLog)
+			}
+			continue
 		}
+		successes = 0
 
 		// 追加 error
-		errorLog = append(errorLog, unpackUnrecoverable(err))
+		errorLog = append(errorLog, Recoverable(err))
 
 		// 用户可以自定义回调函数, 即根据返回的 err 判断是否需要重试
-		if !config.retryIf(err) {
+		// 自定义的 RetryIf 可以决定重试 Unrecoverable 的 err
+		if !config.callRetryIf(err) {
+			if !IsRecoverable(err) {
+				config.ext.onAbort(err, err, config.attempted)
+			}
+			break
+		}
+		if config.ext.unrecoverableIf(err) {
+			config.ext.onAbort(err, err, config.attempted)
+			break
+		}
+
+		// 长时间没有进展, 放弃重试
+		if config.progress.stalled(config.ext.noProgressTimeout) {
+			config.ext.onAbort(ErrNoProgress, err, config.attempted)
+			errorLog = append(errorLog, ErrNoProgress)
 			break
 		}
 
 		// 当重试时, 需要执行的回调函数, 用户可以自定义
-		config.onRetry(n, err)
+		config.callOnRetry(n, err)
 
 		// 用户可以设置某种 err 需要重试几次. 此处会判断返回的 err 并减少需要重试的次数
-		for errToCheck, attempts := range attemptsForError {
-			if errors.Is(err, errToCheck) {
-				attempts--
-				attemptsForError[errToCheck] = attempts
-				shouldRetry = shouldRetry && attempts > 0
+		// 通过 AttemptsForErrorOnly 设置的 err 不计入总的 attempts 次数
+		if attemptsForError != nil {
+			excludedErr := false
+			for errToCheck, attempts := range attemptsForError {
+				if errors.Is(err, errToCheck) {
+					attempts--
+					attemptsForError[errToCheck] = attempts
+					shouldRetry = shouldRetry && attempts > 0
+					excludedErr = excludedErr || config.ext.excludedErrors[errToCheck]
+				}
+			}
+			if excludedErr {
+				excluded++
 			}
 		}
 
 		// 既然最后一次 retryableFunc() 已经执行完了, 那就不需要再等待了
 		// if this is last attempt - don't wait
-		if n == config.attempts-1 {
+		if !shouldRetry || n+1-excluded >= config.attempts {
+			config.exhausted = true
 			break
 		}
 
-		select {
-		case <-config.timer.After(delay(config, n, err)): // 等待一段时间后再重试
-		case <-config.context.Done(): // 如果用户把 context Done() 了, 则退出即可. 通常原因是用户主动 ctx.Cancel() 或者 ctx.Timeout() 自己到达了
-			if config.lastErrorOnly {
-				return emptyT, config.context.Err()
-			}
+		delayTime := delay(config, n, err)
+		// DelayTypeFunc 要求停止重试
+		if delayTime == StopDelay {
+			break
+		}
+		if config.ext.immediateFirstRetry && n == config.ext.startAttempt {
+			delayTime = 0
+		}
+		config.reportProgress(n, n+1-excluded, delayTime, err)
+		config.ext.onState(newState(config.clock.Now(), n+1, delayTime, err))
 
-			return emptyT, append(errorLog, config.context.Err())
+		// 等待一段时间后再重试
+		// 如果用户把 context Done() 了, 则退出即可. 通常原因是用户主动 ctx.Cancel() 或者 ctx.Timeout() 自己到达了
+		if !config.sleepBetweenAttempts(delayTime) {
+			return emptyT, cancelError(config, errorLog)
 		}
 
 		n++
-		shouldRetry = shouldRetry && n < config.attempts // 总的 attempts 次数也会控制是否需要重试
+		shouldRetry = shouldRetry && n-excluded < config.attempts // 总的 attempts 次数也会控制是否需要重试
 	}
 
 	if config.lastErrorOnly {
@@ -233,19 +483,215 @@ func DoWithData[T any](retryableFunc RetryableFuncWithData[T], opts ...Option) (
 	return emptyT, errorLog
 }
 
-func newDefaultRetryConfig() *Config {
-	return &Config{
-		attempts:         uint(10),
-		attemptsForError: make(map[error]uint),
-		delay:            100 * time.Millisecond,
-		maxJitter:        100 * time.Millisecond,
-		onRetry:          func(n uint, err error) {},
-		retryIf:          IsRecoverable, // 通过自定义类型实现
-		delayType:        CombineDelay(BackOffDelay, RandomDelay),
-		lastErrorOnly:    false,
-		context:          context.Background(),
-		timer:            &timerImpl{},
+// singleAttempt checks if the retry ends after the first attempt for sure,
+// i.e. there is no waiting before it and no option which could make another attempt (with Attempts(1))
+func (c *Config) singleAttempt() bool {
+	return c.ext.successThreshold == 1 &&
+		c.attemptsForError == nil &&
+		c.ext.initialDelay <= 0 && c.ext.startAt.IsZero() &&
+		c.ext.windows == nil &&
+		c.ext.noProgressTimeout == 0 && c.progress == nil
+}
+
+// attemptOnce is the retry loop of a single attempt, without the error log and the bookkeeping of retries
+func attemptOnce[T any, F caller[T]](config *Config, retryableFunc F) (T, error) {
+	t, err := attempt[T](config, retryableFunc)
+	if err == nil {
+		return t, nil
+	}
+
+	if !config.callRetryIf(err) {
+		if !IsRecoverable(err) {
+			config.ext.onAbort(err, err, config.attempted)
+		}
+	} else if config.ext.unrecoverableIf(err) {
+		config.ext.onAbort(err, err, config.attempted)
+	} else {
+		config.callOnRetry(config.ext.startAttempt, err)
+		config.exhausted = true
 	}
+
+	var emptyT T
+	if config.lastErrorOnly {
+		return emptyT, Recoverable(err)
+	}
+	return emptyT, Error{Recoverable(err)}
+}
+
+// attempt executes the retryable function once
+func attempt[T any, F caller[T]](config *Config, retryableFunc F) (T, error) {
+	if !config.waitForPacer() {
+		var emptyT T
+		return emptyT, contextCause(config.context)
+	}
+	if config.ext.semaphore != nil {
+		if err := config.ext.semaphore.Acquire(config.context, 1); err != nil {
+			var emptyT T
+			return emptyT, err
+		}
+		defer config.ext.semaphore.Release(1)
+	}
+
+	config.attempted++
+	config.budget.update(config)
+	if config.ext.stats != nil {
+		config.ext.stats.AttemptStarted()
+	}
+	if config.traceContext != nil {
+		defer trace.StartRegion(config.traceContext, "retry.attempt").End()
+	}
+
+	if config.ext.beforeAttempt != nil {
+		config.ext.beforeAttempt(config.attempted)
+	}
+	// 只有需要记录执行时间时才读取时钟
+	timed := config.timed()
+	var start, end time.Time
+	if timed {
+		start = config.clock.Now()
+	}
+	t, err := call[T](config, retryableFunc)
+	if err == nil {
+		if fault := config.injectFault(); fault != nil {
+			var emptyT T
+			t, err = emptyT, fault
+		}
+	}
+	if timed {
+		end = config.clock.Now()
+		config.ext.recorder.record(config, start, end, err)
+	}
+	if err != nil {
+		config.ext.onCleanup(config.attempted, err)
+		if config.ext.stats != nil {
+			config.ext.stats.AttemptFailed(err, end.Sub(start))
+		}
+		if config.traceContext != nil {
+			trace.Logf(config.traceContext, "retry", "attempt #%d failed: %v", config.attempted, err)
+		}
+	}
+	return t, err
+}
+
+// timed checks if the attempts are timed, i.e. there is a Recorder or a StatsCollector
+func (c *Config) timed() bool {
+	return c.ext.recorder != nil || c.ext.stats != nil
+}
+
+// sleep waits for the given duration, returns false when the context is done in the meantime
+// (or would be done before the end of the sleep with DeadlineAwareDelay)
+func (c *Config) sleep(d time.Duration) bool {
+	if c.traceContext != nil {
+		defer trace.StartRegion(c.traceContext, "retry.sleep").End()
+	}
+
+	if c.ext.deadlineAware {
+		if deadline, ok := c.context.Deadline(); ok {
+			if remaining := deadline.Sub(c.clock.Now()) - c.ext.deadlineMargin; d >= remaining {
+				if remaining > 0 {
+					select {
+					case <-c.timer.After(remaining):
+					case <-c.context.Done():
+					}
+				}
+				return false
+			}
+		}
+	}
+
+	select {
+	case <-c.timer.After(d):
+		return true
+	case <-c.context.Done():
+		return false
+	}
+}
+
+// sleepBetweenAttempts is sleep with the delay after an attempt,
+// or waiting for the dependency to be healthy with WithHealthCheck
+func (c *Config) sleepBetweenAttempts(d time.Duration) bool {
+	if c.ext.healthCheck != nil {
+		return c.waitHealthy()
+	}
+
+	c.ext.onDelay(c.attempted, d)
+	c.ext.recorder.recordDelay(c, d)
+	if c.ext.noDelay {
+		return c.context.Err() == nil
+	}
+	return c.sleep(d)
+}
+
+// contextErr returns the error of the context after an interrupted sleep,
+// context.DeadlineExceeded when the sleep was cut short by DeadlineAwareDelay before the context expired
+func (c *Config) contextErr() error {
+	if c.context.Err() == nil {
+		return context.DeadlineExceeded
+	}
+	return contextCause(c.context)
+}
+
+// cancelError returns the error of cancelled retry: the error log followed by the context error
+func cancelError(config *Config, errorLog Error) error {
+	reason := config.contextErr()
+	var lastErr error
+	if len(errorLog) > 0 {
+		lastErr = errorLog[len(errorLog)-1]
+	}
+	config.ext.onAbort(reason, lastErr, config.attempted)
+
+	if config.lastErrorOnly || len(errorLog) == 0 {
+		return reason
+	}
+	return append(errorLog, reason)
+}
+
+// abortError returns the error of cancelled infinite retry:
+// the error history followed by the context error (if the history is recorded)
+// or the context error optionally wrapped with the last error.
+func abortError(config *Config, history Error, lastErr error) error {
+	reason := config.contextErr()
+	config.ext.onAbort(reason, lastErr, config.attempted)
+
+	if len(history) > 0 {
+		return append(history, reason)
+	}
+	if config.wrapContextErrorWithLastError && lastErr != nil {
+		return Error{reason, lastErr}
+	}
+	return reason
+}
+
+// defaultRetryConfig is copied by newDefaultRetryConfig, so the defaults aren't built by every call.
+// It must not be modified; options replace its maps instead of writing to them (see setAttemptsForError).
+// attemptsForError is nil until AttemptsForError is used.
+var defaultRetryConfig = Config{
+	attempts:      uint(10),
+	delay:         100 * time.Millisecond,
+	maxJitter:     100 * time.Millisecond,
+	onRetry:       func(n uint, err error) {},
+	retryIf:       IsRetryable, // 通过自定义类型实现
+	delayType:     CombineDelay(BackOffDelayPure, RandomDelay),
+	lastErrorOnly: false,
+	context:       context.Background(),
+	timer:         &timerImpl{},
+	clock:         &clockImpl{},
+	ext:           &defaultConfigExt,
+}
+
+// defaultConfigExt is shared by all configs until modified, see extend
+var defaultConfigExt = configExt{
+	unrecoverableIf:  func(err error) bool { return false },
+	onState:          func(state State) {},
+	onAbort:          func(reason error, lastErr error, n uint) {},
+	onDelay:          func(n uint, d time.Duration) {},
+	onCleanup:        func(n uint, err error) {},
+	successThreshold: 1,
+}
+
+func newDefaultRetryConfig() *Config {
+	config := defaultRetryConfig
+	return &config
 }
 
 // Error type represents list of errors in retry
@@ -325,13 +771,49 @@ func (e unrecoverableError) Unwrap() error {
 }
 
 // Unrecoverable wraps an error in `unrecoverableError` struct
+// Unrecoverable(nil) returns nil, so it is safe to wrap a result unconditionally
 func Unrecoverable(err error) error {
+	if err == nil {
+		return nil
+	}
 	return unrecoverableError{err}
 }
 
 // IsRecoverable checks if error is an instance of `unrecoverableError`
 func IsRecoverable(err error) bool {
-	return !errors.Is(err, unrecoverableError{})
+	return !IsUnrecoverable(err)
+}
+
+// IsUnrecoverable checks if error is (or wraps) an instance of `unrecoverableError`
+func IsUnrecoverable(err error) bool {
+	return errors.Is(err, unrecoverableError{})
+}
+
+// IsRetryable is the default RetryIfFunc.
+// It returns false for unrecoverable errors and otherwise honors
+// `Retryable() bool` or `Temporary() bool` methods found in the error chain,
+// so errors annotated by libraries are respected without a custom RetryIf.
+// `Temporary()` of net.Error is deprecated and ill-defined, thus it is ignored
+// for errors which also implement `Timeout() bool`.
+// Errors without any annotation are retried.
+func IsRetryable(err error) bool {
+	if !IsRecoverable(err) {
+		return false
+	}
+
+	var retryable interface{ Retryable() bool }
+	if errors.As(err, &retryable) {
+		return retryable.Retryable()
+	}
+
+	var temporary interface{ Temporary() bool }
+	if errors.As(err, &temporary) {
+		if _, isNetError := temporary.(interface{ Timeout() bool }); !isNetError {
+			return temporary.Temporary()
+		}
+	}
+
+	return true
 }
 
 // Adds support for errors.Is usage on unrecoverableError
@@ -340,7 +822,12 @@ func (unrecoverableError) Is(err error) bool {
 	return isUnrecoverable
 }
 
-func unpackUnrecoverable(err error) error {
+// Recoverable removes the `unrecoverableError` wrapper added by `Unrecoverable`, if any,
+// so a middle layer can override the "do not retry" decision of a lower layer.
+// Only the outermost wrapper is removed: an error wrapping an unrecoverable error
+// (e.g. `fmt.Errorf("x: %w", Unrecoverable(err))`) is returned as is and stays unrecoverable,
+// so call Recoverable before wrapping the error.
+func Recoverable(err error) error {
 	if unrecoverable, isUnrecoverable := err.(unrecoverableError); isUnrecoverable {
 		return unrecoverable.error
 	}
@@ -348,11 +835,59 @@ func unpackUnrecoverable(err error) error {
 	return err
 }
 
+// RetryAfter returns the delay suggested by `err` and whether there is one.
+// The suggestion is taken from the first error in the chain implementing
+// `RetryAfter() time.Duration`; negative durations are ignored.
+func RetryAfter(err error) (time.Duration, bool) {
+	var hint interface{ RetryAfter() time.Duration }
+	if !errors.As(err, &hint) {
+		return 0, false
+	}
+
+	d := hint.RetryAfter()
+	if d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
 func delay(config *Config, n uint, err error) time.Duration {
-	delayTime := config.delayType(n, err, config)
+	if config.ext.noDelay {
+		return 0
+	}
+
+	delayTime, ok := RetryAfter(err)
+	if !ok {
+		var at time.Time
+		if at, ok = NextAttemptAt(err); ok {
+			delayTime = untilTime(config, at)
+		}
+	}
+	if !ok {
+		delayTime, ok = config.scheduledDelay(n + config.ext.delayOffset)
+	}
+	if !ok {
+		delayTime = config.callDelayType(n+config.ext.delayOffset, err)
+		if delayTime == StopDelay {
+			return StopDelay
+		}
+	}
 	if config.maxDelay > 0 && delayTime > config.maxDelay {
 		delayTime = config.maxDelay
 	}
+	if config.ext.proportionalJitter > 0 && delayTime > 0 {
+		jitter := config.jitter(func() time.Duration {
+			return time.Duration(float64(delayTime) * config.ext.proportionalJitter / 100 * config.random().Float64())
+		})
+		if jitter > math.MaxInt64-delayTime {
+			jitter = math.MaxInt64 - delayTime
+		}
+		delayTime += jitter
+		// MaxDelay caps also the jitter
+		if config.maxDelay > 0 && delayTime > config.maxDelay {
+			delayTime = config.maxDelay
+		}
+	}
 
 	return delayTime
 }
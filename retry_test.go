@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"testing"
 	"time"
@@ -315,6 +316,10 @@ func TestBackOffDelay(t *testing.T) {
 				config := Config{
 					delay: c.delay,
 				}
+				pure := BackOffDelayPure(c.n, nil, &config)
+				assert.Equal(t, Config{delay: c.delay}, config, "config is not modified")
+				assert.Equal(t, c.expectedDelay, pure, "pure delay duration mismatch")
+
 				delay := BackOffDelay(c.n, nil, &config)
 				assert.Equal(t, c.expectedMaxN, config.maxBackOffN, "max n mismatch")
 				assert.Equal(t, c.expectedDelay, delay, "delay duration mismatch")
@@ -630,6 +635,24 @@ func BenchmarkDoWithDataNoErrors(b *testing.B) {
 	}
 }
 
+// BenchmarkDoWrappedNoErrors is BenchmarkDoNoErrors with the function wrapped by a closure for DoWithData,
+// as Do did before the retry loop took any caller; the closure costs an allocation per call
+func BenchmarkDoWrappedNoErrors(b *testing.B) {
+	retryableFunc := RetryableFunc(func() error {
+		return nil
+	})
+
+	for i := 0; i < b.N; i++ {
+		_, _ = DoWithData(
+			func() (any, error) {
+				return nil, retryableFunc()
+			},
+			Attempts(10),
+			Delay(0),
+		)
+	}
+}
+
 func TestIsRecoverable(t *testing.T) {
 	err := errors.New("err")
 	assert.True(t, IsRecoverable(err))
@@ -640,3 +663,717 @@ func TestIsRecoverable(t *testing.T) {
 	err = fmt.Errorf("wrapping: %w", err)
 	assert.False(t, IsRecoverable(err))
 }
+
+func TestIsUnrecoverable(t *testing.T) {
+	err := errors.New("err")
+	assert.False(t, IsUnrecoverable(err))
+	assert.False(t, IsUnrecoverable(nil))
+
+	err = Unrecoverable(err)
+	assert.True(t, IsUnrecoverable(err))
+
+	err = fmt.Errorf("wrapping: %w", err)
+	assert.True(t, IsUnrecoverable(err))
+}
+
+func TestUnrecoverableNil(t *testing.T) {
+	assert.Nil(t, Unrecoverable(nil))
+
+	attempts := 0
+	err := Do(
+		func() error {
+			attempts++
+			return Unrecoverable(nil)
+		},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestUnrecoverableIf(t *testing.T) {
+	fatalErr := errors.New("fatal")
+	attempts := 0
+	err := Do(
+		func() error {
+			attempts++
+			if attempts == 3 {
+				return fatalErr
+			}
+			return errors.New("test")
+		},
+		UnrecoverableIf(func(err error) bool { return errors.Is(err, fatalErr) }),
+		RetryIf(func(err error) bool { return true }),
+		Delay(time.Nanosecond),
+	)
+	assert.ErrorIs(t, err, fatalErr)
+	assert.Len(t, err, 3)
+	assert.Equal(t, 3, attempts)
+
+	attempts = 0
+	err = Do(
+		func() error {
+			attempts++
+			return fatalErr
+		},
+		UnrecoverableIf(func(err error) bool { return errors.Is(err, fatalErr) }),
+		Attempts(0),
+		Delay(time.Nanosecond),
+	)
+	assert.Equal(t, fatalErr, err)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestRecoverable(t *testing.T) {
+	testErr := errors.New("err")
+	assert.Equal(t, testErr, Recoverable(testErr))
+	assert.Equal(t, testErr, Recoverable(Unrecoverable(testErr)))
+	assert.True(t, IsRecoverable(Recoverable(Unrecoverable(testErr))))
+	assert.Nil(t, Recoverable(nil))
+
+	// only the outermost wrapper is removed
+	wrapped := fmt.Errorf("wrap: %w", Unrecoverable(testErr))
+	assert.Equal(t, wrapped, Recoverable(wrapped))
+	assert.True(t, IsUnrecoverable(Recoverable(wrapped)))
+	assert.True(t, IsRecoverable(fmt.Errorf("wrap: %w", Recoverable(Unrecoverable(testErr)))))
+
+	attempts := 0
+	err := Do(
+		func() error {
+			attempts++
+			return Recoverable(Unrecoverable(testErr))
+		},
+		Attempts(3),
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+type retryableErr bool
+
+func (e retryableErr) Error() string   { return fmt.Sprintf("retryable: %t", bool(e)) }
+func (e retryableErr) Retryable() bool { return bool(e) }
+
+type temporaryErr bool
+
+func (e temporaryErr) Error() string   { return fmt.Sprintf("temporary: %t", bool(e)) }
+func (e temporaryErr) Temporary() bool { return bool(e) }
+
+type netErr struct{ temporaryErr }
+
+func (e netErr) Timeout() bool { return false }
+
+func TestIsRetryable(t *testing.T) {
+	for _, c := range []struct {
+		label    string
+		err      error
+		expected bool
+	}{
+		{"plain", errors.New("err"), true},
+		{"unrecoverable", Unrecoverable(retryableErr(true)), false},
+		{"retryable", retryableErr(true), true},
+		{"not retryable", retryableErr(false), false},
+		{"wrapped not retryable", fmt.Errorf("wrap: %w", retryableErr(false)), false},
+		{"temporary", temporaryErr(true), true},
+		{"not temporary", temporaryErr(false), false},
+		{"net error not temporary", netErr{temporaryErr(false)}, true},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			assert.Equal(t, c.expected, IsRetryable(c.err))
+		})
+	}
+
+	attempts := 0
+	err := Do(
+		func() error {
+			attempts++
+			return retryableErr(false)
+		},
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+type retryAfterErr time.Duration
+
+func (e retryAfterErr) Error() string             { return "retry after" }
+func (e retryAfterErr) RetryAfter() time.Duration { return time.Duration(e) }
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := RetryAfter(errors.New("err"))
+	assert.False(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	d, ok = RetryAfter(fmt.Errorf("wrap: %w", retryAfterErr(time.Second)))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+
+	_, ok = RetryAfter(retryAfterErr(-time.Second))
+	assert.False(t, ok)
+
+	config := newDefaultRetryConfig()
+	config.delayType = FixedDelay
+	assert.Equal(t, time.Second, delay(config, 0, retryAfterErr(time.Second)))
+	assert.Equal(t, config.delay, delay(config, 0, errors.New("err")))
+
+	config.maxDelay = time.Millisecond
+	assert.Equal(t, time.Millisecond, delay(config, 0, retryAfterErr(time.Second)))
+}
+
+func TestSuccessThreshold(t *testing.T) {
+	results := []error{nil, errors.New("1"), nil, nil, errors.New("2"), nil, nil, nil}
+	calls := 0
+	err := Do(
+		func() error {
+			err := results[calls]
+			calls++
+			return err
+		},
+		SuccessThreshold(3),
+		Attempts(3),
+		Delay(time.Nanosecond),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, len(results), calls)
+
+	calls = 0
+	err = Do(
+		func() error {
+			err := results[calls]
+			calls++
+			return err
+		},
+		SuccessThreshold(3),
+		Attempts(2),
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Len(t, err, 2)
+	assert.Equal(t, 5, calls)
+
+	calls = 0
+	err = Do(
+		func() error {
+			err := results[calls]
+			calls++
+			return err
+		},
+		SuccessThreshold(3),
+		Attempts(0),
+		Delay(time.Nanosecond),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, len(results), calls)
+}
+
+func TestInitialDelay(t *testing.T) {
+	var timer recordingTimer
+	attempts := 0
+	err := Do(
+		func() error {
+			attempts++
+			if attempts < 2 {
+				return errors.New("test")
+			}
+			return nil
+		},
+		InitialDelay(time.Second),
+		Delay(time.Millisecond),
+		DelayType(FixedDelay),
+		WithTimer(&timer),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Second, time.Millisecond}, timer.delays)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	attempts = 0
+	err = Do(
+		func() error {
+			attempts++
+			return nil
+		},
+		InitialDelay(time.Hour),
+		Context(ctx),
+	)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 0, attempts)
+}
+
+func TestImmediateFirstRetry(t *testing.T) {
+	var timer recordingTimer
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(4),
+		ImmediateFirstRetry(true),
+		Delay(time.Millisecond),
+		DelayType(BackOffDelay),
+		WithTimer(&timer),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []time.Duration{0, 2 * time.Millisecond, 4 * time.Millisecond}, timer.delays)
+
+	timer.delays = nil
+	attempts := 0
+	err = Do(
+		func() error {
+			attempts++
+			if attempts < 3 {
+				return errors.New("test")
+			}
+			return nil
+		},
+		Attempts(0),
+		ImmediateFirstRetry(true),
+		Delay(time.Millisecond),
+		DelayType(FixedDelay),
+		WithTimer(&timer),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{0, time.Millisecond}, timer.delays)
+}
+
+func TestAttemptsForErrorOnly(t *testing.T) {
+	throttled := errors.New("throttled")
+	other := errors.New("other")
+
+	count := 0
+	err := Do(
+		func() error {
+			count++
+			if count <= 10 {
+				return throttled
+			}
+			return other
+		},
+		AttemptsForErrorOnly(20, throttled),
+		Attempts(3),
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 13, count, "10 throttled retries are not counted against 3 attempts")
+
+	count = 0
+	err = Do(
+		func() error {
+			count++
+			return throttled
+		},
+		AttemptsForErrorOnly(4, throttled),
+		Attempts(10),
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 4, count, "exhausted per-error budget stops the retry")
+}
+
+func TestErrorHistory(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	count := 0
+	err := Do(
+		func() error {
+			count++
+			if count == 5 {
+				cancel()
+			}
+			return fmt.Errorf("error %d", count)
+		},
+		Context(ctx),
+		Attempts(0),
+		Delay(time.Nanosecond),
+		ErrorHistory(3),
+	)
+
+	expectedErrorFormat := `All attempts fail:
+#1: error 3
+#2: error 4
+#3: error 5
+#4: context canceled`
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, expectedErrorFormat, err.Error())
+}
+
+// fakeClock advances its time by every requested sleep
+type fakeClock struct {
+	now    time.Time
+	delays []time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.delays = append(c.delays, d)
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func TestWithClock(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 8, 0, 0, 0, time.Local)}
+
+	var states []State
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(3),
+		Delay(time.Minute),
+		DelayType(FixedDelay),
+		AllowedWindow(9*time.Hour, 17*time.Hour),
+		OnState(func(state State) { states = append(states, state) }),
+		WithClock(clock),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []time.Duration{time.Hour, time.Minute, time.Minute}, clock.delays)
+	assert.Len(t, states, 2)
+	assert.Equal(t, time.Date(2024, time.March, 6, 9, 1, 0, 0, time.Local), states[0].NextRunAt)
+}
+
+func TestDeadlineAwareDelay(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	ctx, cancel := context.WithDeadline(context.Background(), clock.now.Add(time.Hour))
+	defer cancel()
+
+	err := Do(
+		func() error { return errors.New("test") },
+		Delay(25*time.Minute),
+		DelayType(FixedDelay),
+		Context(ctx),
+		WithClock(clock),
+		DeadlineAwareDelay(time.Minute),
+	)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, err.(Error), 4)
+	assert.NoError(t, ctx.Err(), "returned before the deadline")
+	assert.Equal(t, []time.Duration{25 * time.Minute, 25 * time.Minute, 9 * time.Minute}, clock.delays)
+}
+
+func TestOnAbort(t *testing.T) {
+	type abort struct {
+		reason, lastErr error
+		n               uint
+	}
+
+	t.Run("unrecoverable", func(t *testing.T) {
+		var aborts []abort
+		errFatal := errors.New("fatal")
+		calls := 0
+		err := Do(func() error {
+			calls++
+			if calls == 2 {
+				return Unrecoverable(errFatal)
+			}
+			return errors.New("test")
+		}, Delay(0), OnAbort(func(reason, lastErr error, n uint) {
+			aborts = append(aborts, abort{reason, lastErr, n})
+		}))
+		assert.Error(t, err)
+		assert.Len(t, aborts, 1)
+		assert.ErrorIs(t, aborts[0].reason, errFatal)
+		assert.Equal(t, uint(2), aborts[0].n)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		var aborts []abort
+		ctx, cancel := context.WithCancel(context.Background())
+		errTest := errors.New("test")
+		err := Do(func() error {
+			cancel()
+			return errTest
+		}, Delay(time.Hour), Context(ctx), OnAbort(func(reason, lastErr error, n uint) {
+			aborts = append(aborts, abort{reason, lastErr, n})
+		}))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []abort{{context.Canceled, errTest, 1}}, aborts)
+	})
+
+	t.Run("cancelled infinite", func(t *testing.T) {
+		var aborts []abort
+		ctx, cancel := context.WithCancel(context.Background())
+		errTest := errors.New("test")
+		calls := 0
+		err := Do(func() error {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			return errTest
+		}, Attempts(0), Delay(10*time.Millisecond), DelayType(FixedDelay), Context(ctx), OnAbort(func(reason, lastErr error, n uint) {
+			aborts = append(aborts, abort{reason, lastErr, n})
+		}))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, []abort{{context.Canceled, errTest, 3}}, aborts)
+	})
+
+	t.Run("RetryIf retrying unrecoverable", func(t *testing.T) {
+		aborted := false
+		calls := 0
+		err := Do(func() error {
+			calls++
+			return Unrecoverable(errors.New("test"))
+		}, Attempts(3), Delay(0), RetryIf(func(err error) bool { return true }),
+			OnAbort(func(reason, lastErr error, n uint) { aborted = true }))
+		assert.Error(t, err)
+		assert.Equal(t, 3, calls)
+		assert.False(t, aborted)
+	})
+
+	t.Run("not called when exhausted", func(t *testing.T) {
+		aborted := false
+		err := Do(func() error { return errors.New("test") },
+			Attempts(2), Delay(0), OnAbort(func(reason, lastErr error, n uint) { aborted = true }))
+		assert.Error(t, err)
+		assert.False(t, aborted)
+	})
+}
+
+func TestOnDelay(t *testing.T) {
+	type delayed struct {
+		n uint
+		d time.Duration
+	}
+	var delays []delayed
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(4),
+		Delay(10*time.Millisecond),
+		MaxDelay(15*time.Millisecond),
+		DelayType(BackOffDelay),
+		ImmediateFirstRetry(true),
+		OnDelay(func(n uint, d time.Duration) { delays = append(delays, delayed{n, d}) }),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []delayed{{1, 0}, {2, 15 * time.Millisecond}, {3, 15 * time.Millisecond}}, delays)
+}
+
+func TestKeyedJitter(t *testing.T) {
+	config := newDefaultRetryConfig()
+	config.maxJitter = time.Second
+
+	jitters := func(key string) []time.Duration {
+		var ds []time.Duration
+		for n := uint(0); n < 5; n++ {
+			d := KeyedJitter(key)(n, nil, config)
+			assert.GreaterOrEqual(t, d, time.Duration(0))
+			assert.Less(t, d, time.Second)
+			ds = append(ds, d)
+		}
+		return ds
+	}
+
+	assert.Equal(t, jitters("host-a"), jitters("host-a"), "stable for a key")
+	assert.NotEqual(t, jitters("host-a"), jitters("host-b"), "differs between keys")
+
+	config.maxJitter = 0
+	assert.Equal(t, time.Duration(0), KeyedJitter("host-a")(1, nil, config))
+}
+
+func TestRandomizationFactor(t *testing.T) {
+	delays, err := Plan(Attempts(50), Delay(time.Second), MaxDelay(8*time.Second), DelayType(BackOffDelay), RandomizationFactor(0.5))
+	assert.NoError(t, err)
+
+	randomized := false
+	for n, d := range delays[:3] {
+		base := time.Second << n
+		assert.GreaterOrEqual(t, d, base/2)
+		assert.LessOrEqual(t, d, base+base/2)
+	}
+	for _, d := range delays {
+		randomized = randomized || d != delays[0]
+		assert.LessOrEqual(t, d, 8*time.Second)
+	}
+	assert.True(t, randomized)
+
+	delays, _ = Plan(Attempts(3), Delay(time.Second), DelayType(BackOffDelay), RandomizationFactor(0))
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, delays)
+
+	assert.ErrorIs(t, Validate(RandomizationFactor(1.5)), ErrInvalidOption)
+}
+
+func TestJitterRange(t *testing.T) {
+	delays, err := Plan(Attempts(100), DelayType(RandomDelay), JitterRange(2*time.Second, 5*time.Second))
+	assert.NoError(t, err)
+	for _, d := range delays {
+		assert.GreaterOrEqual(t, d, 2*time.Second)
+		assert.Less(t, d, 5*time.Second)
+	}
+
+	delays, _ = Plan(Attempts(2), DelayType(RandomDelay), JitterRange(time.Second, time.Second))
+	assert.Equal(t, []time.Duration{time.Second}, delays)
+
+	delays, _ = Plan(Attempts(2), DelayType(RandomDelay), MaxJitter(0))
+	assert.Equal(t, []time.Duration{0}, delays, "no jitter instead of a panic")
+
+	assert.ErrorIs(t, Validate(JitterRange(time.Second, 0)), ErrInvalidOption)
+}
+
+func TestProportionalJitter(t *testing.T) {
+	delays, err := Plan(Attempts(4), Delay(time.Second), DelayType(BackOffDelay), ProportionalJitter(20))
+	assert.NoError(t, err)
+	for n, d := range delays {
+		base := time.Second << n
+		assert.GreaterOrEqual(t, d, base)
+		assert.LessOrEqual(t, d, base+base/5)
+	}
+
+	delays, err = Plan(Attempts(4), Delay(10*time.Second), DelayType(BackOffDelay), MaxDelay(15*time.Second), ProportionalJitter(20))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{15 * time.Second, 15 * time.Second}, delays[1:], "MaxDelay caps the jitter")
+
+	recording := &Recording{}
+	delays, err = Plan(Attempts(4), Delay(time.Second), DelayType(BackOffDelay), ProportionalJitter(20), RecordJitter(recording))
+	assert.NoError(t, err)
+	assert.Len(t, recording.Jitters, 3)
+	replayed, err := Plan(Attempts(4), Delay(time.Second), DelayType(BackOffDelay), ProportionalJitter(20), WithReplay(recording))
+	assert.NoError(t, err)
+	assert.Equal(t, delays, replayed)
+
+	assert.ErrorIs(t, Validate(ProportionalJitter(-1)), ErrInvalidOption)
+}
+
+func TestNoDelay(t *testing.T) {
+	timer := &recordingTimer{}
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return retryAfterErr(time.Hour)
+	}, Attempts(3), NoDelay(), WithTimer(timer))
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Empty(t, timer.delays, "the timer is not used")
+
+	delays, _ := Plan(Attempts(3), NoDelay())
+	assert.Equal(t, []time.Duration{0, 0}, delays)
+}
+
+func TestStopDelay(t *testing.T) {
+	stopErr := errors.New("never")
+	stopOnErr := func(n uint, err error, config *Config) time.Duration {
+		if errors.Is(err, stopErr) {
+			return StopDelay
+		}
+		return time.Millisecond
+	}
+
+	calls := 0
+	err := Do(func() error {
+		calls++
+		if calls == 2 {
+			return stopErr
+		}
+		return errors.New("test")
+	}, Attempts(5), DelayType(CombineDelay(stopOnErr, RandomDelay)))
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+	assert.ErrorIs(t, err, stopErr)
+
+	calls = 0
+	err = Do(func() error {
+		calls++
+		if calls == 3 {
+			return stopErr
+		}
+		return errors.New("test")
+	}, Attempts(0), DelayType(stopOnErr))
+	assert.Equal(t, stopErr, err)
+	assert.Equal(t, 3, calls)
+
+	delays, _ := Plan(Attempts(5), DelayType(func(n uint, _ error, _ *Config) time.Duration {
+		if n == 2 {
+			return StopDelay
+		}
+		return time.Second
+	}))
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, delays)
+}
+
+func TestDefaultRetryConfigNotModified(t *testing.T) {
+	testErr := errors.New("test")
+	config := newRetryConfig([]Option{AttemptsForError(2, testErr), AttemptsForErrorOnly(3, io.EOF)})
+	assert.Equal(t, map[error]uint{testErr: 2, io.EOF: 3}, config.attemptsForError)
+	assert.Nil(t, defaultRetryConfig.attemptsForError, "allocated only when used")
+	assert.Nil(t, newDefaultRetryConfig().attemptsForError)
+}
+
+func TestSingleAttempt(t *testing.T) {
+	testErr := errors.New("test")
+
+	var retries []uint
+	err := Do(func() error { return testErr }, Attempts(1), OnRetry(func(n uint, err error) {
+		retries = append(retries, n)
+	}))
+	assert.Equal(t, Error{testErr}, err)
+	assert.Equal(t, []uint{0}, retries, "OnRetry is called as by the retry loop")
+
+	err = Do(func() error { return Unrecoverable(testErr) }, Attempts(1), LastErrorOnly(true))
+	assert.Equal(t, testErr, err)
+
+	v, err := DoWithData(func() (int, error) { return 42, nil }, Attempts(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+
+	calls := 0
+	err = Do(func() error {
+		calls++
+		return testErr
+	}, Attempts(1), AttemptsForErrorOnly(2, testErr), Delay(0))
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls, "excluded errors still retry")
+}
+
+func BenchmarkDoSingleAttempt(b *testing.B) {
+	testError := errors.New("test error")
+
+	for i := 0; i < b.N; i++ {
+		_ = Do(
+			func() error {
+				return testError
+			},
+			Attempts(1),
+			LastErrorOnly(true),
+		)
+	}
+}
+
+func TestOnCleanup(t *testing.T) {
+	var events []string
+	err := Do(
+		func() error {
+			events = append(events, "attempt")
+			return errors.New("test")
+		},
+		Attempts(2),
+		Delay(0),
+		OnRetry(func(n uint, err error) { events = append(events, "retry") }),
+		OnCleanup(func(n uint, err error) {
+			events = append(events, fmt.Sprintf("cleanup #%d: %v", n, err))
+		}),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"attempt", "cleanup #1: test", "retry", "attempt", "cleanup #2: test", "retry"}, events)
+
+	events = nil
+	assert.NoError(t, Do(func() error { return nil }, OnCleanup(func(n uint, err error) {
+		events = append(events, "cleanup")
+	})))
+	assert.Empty(t, events, "not called after a successful attempt")
+}
+
+func TestBeforeAttempt(t *testing.T) {
+	var events []string
+	err := Do(
+		func() error {
+			events = append(events, "attempt")
+			return errors.New("test")
+		},
+		Attempts(3),
+		Delay(0),
+		BeforeAttempt(func(n uint) { events = append(events, fmt.Sprintf("before #%d", n)) }),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []string{"before #1", "attempt", "before #2", "attempt", "before #3", "attempt"}, events)
+}
@@ -0,0 +1,17 @@
+module github.com/avast/retry-go/v4/retryamqp
+
+go 1.18
+
+require (
+	github.com/avast/retry-go/v4 v4.5.0
+	github.com/rabbitmq/amqp091-go v1.9.0
+	github.com/stretchr/testify v1.8.4
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/avast/retry-go/v4 => ../
@@ -0,0 +1,139 @@
+/*
+Package retryamqp provides AMQP helpers for github.com/avast/retry-go
+
+It is a separate module, so the core package does not depend on an AMQP client.
+
+retry handling of deliveries and dead-letter those which keep failing:
+
+	deadLetter := retryamqp.DeadLetter{Publisher: ch, Exchange: "orders.dlx"}
+
+	for d := range deliveries {
+		err := retryamqp.Handle(ctx, d, handle, deadLetter, retry.Attempts(5))
+		var dead *retryamqp.DeadLetteredError
+		if err == nil || errors.As(err, &dead) {
+			d.Ack(false)
+		} else {
+			d.Nack(false, true)
+		}
+	}
+*/
+package retryamqp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/avast/retry-go/v4"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Headers set on dead-lettered messages (in addition to the original headers)
+const (
+	// HeaderAttempts is the count of failed attempts (int32)
+	HeaderAttempts = "x-retry-attempts"
+	// HeaderErrors are the messages of the errors of the attempts ([]interface{} of strings)
+	HeaderErrors = "x-retry-errors"
+	// HeaderOriginalExchange is the exchange the message was originally published to
+	HeaderOriginalExchange = "x-retry-original-exchange"
+	// HeaderOriginalRoutingKey is the routing key the message was originally published with
+	HeaderOriginalRoutingKey = "x-retry-original-routing-key"
+)
+
+// Publisher publishes messages, *amqp.Channel satisfies Publisher.
+type Publisher interface {
+	PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error
+}
+
+// DeadLetter is where the messages are published when their handling keeps failing.
+// An empty RoutingKey keeps the original routing key of the message.
+type DeadLetter struct {
+	Publisher  Publisher
+	Exchange   string
+	RoutingKey string
+}
+
+// DeadLetteredError is returned by `Handle` when all attempts failed and the message was published
+// to the dead-letter exchange, so the delivery can be acknowledged.
+type DeadLetteredError struct {
+	// Err is the error returned by the retry, usually `retry.Error`
+	Err error
+}
+
+func (e *DeadLetteredError) Error() string {
+	return fmt.Sprintf("message dead-lettered: %s", e.Err)
+}
+
+func (e *DeadLetteredError) Unwrap() error {
+	return e.Err
+}
+
+// Handle retries the handler with the given options and, when all attempts fail
+// (or the error is unrecoverable), publishes the message with the errors of the attempts
+// (see the Header constants) to the dead-letter exchange and returns `*DeadLetteredError`.
+//
+// When the context is done the message is not dead-lettered and the context error is returned,
+// so the delivery can be requeued. Errors of publishing are returned wrapped together with the retry error.
+func Handle(ctx context.Context, d amqp.Delivery, handler func(ctx context.Context, d amqp.Delivery) error, deadLetter DeadLetter, opts ...retry.Option) error {
+	err := retry.DoContext(ctx, func(ctx context.Context) error {
+		return handler(ctx, d)
+	}, opts...)
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return err
+	}
+
+	key := deadLetter.RoutingKey
+	if key == "" {
+		key = d.RoutingKey
+	}
+	if pubErr := deadLetter.Publisher.PublishWithContext(ctx, deadLetter.Exchange, key, false, false, deadLetterPublishing(d, err)); pubErr != nil {
+		return fmt.Errorf("dead-letter publishing failed: %w (retry error: %s)", pubErr, err)
+	}
+
+	return &DeadLetteredError{Err: err}
+}
+
+// deadLetterPublishing copies the delivery and adds the retry metadata to its headers
+func deadLetterPublishing(d amqp.Delivery, err error) amqp.Publishing {
+	errs := []error{err}
+	var retryErr retry.Error
+	if errors.As(err, &retryErr) {
+		errs = retryErr.WrappedErrors()
+	}
+
+	messages := make([]interface{}, 0, len(errs))
+	for _, e := range errs {
+		if e != nil {
+			messages = append(messages, e.Error())
+		}
+	}
+
+	headers := make(amqp.Table, len(d.Headers)+4)
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[HeaderAttempts] = int32(len(errs))
+	headers[HeaderErrors] = messages
+	headers[HeaderOriginalExchange] = d.Exchange
+	headers[HeaderOriginalRoutingKey] = d.RoutingKey
+
+	return amqp.Publishing{
+		Headers:         headers,
+		ContentType:     d.ContentType,
+		ContentEncoding: d.ContentEncoding,
+		DeliveryMode:    d.DeliveryMode,
+		Priority:        d.Priority,
+		CorrelationId:   d.CorrelationId,
+		ReplyTo:         d.ReplyTo,
+		Expiration:      d.Expiration,
+		MessageId:       d.MessageId,
+		Timestamp:       d.Timestamp,
+		Type:            d.Type,
+		UserId:          d.UserId,
+		AppId:           d.AppId,
+		Body:            d.Body,
+	}
+}
@@ -0,0 +1,97 @@
+package retryamqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type published struct {
+	exchange, key string
+	msg           amqp.Publishing
+}
+
+type publisher struct {
+	published []published
+	err       error
+}
+
+func (p *publisher) PublishWithContext(_ context.Context, exchange, key string, _, _ bool, msg amqp.Publishing) error {
+	p.published = append(p.published, published{exchange: exchange, key: key, msg: msg})
+	return p.err
+}
+
+func TestHandle(t *testing.T) {
+	p := &publisher{}
+	calls := 0
+	err := Handle(context.Background(), amqp.Delivery{Body: []byte("body")}, func(ctx context.Context, d amqp.Delivery) error {
+		calls++
+		if calls < 2 {
+			return errors.New("test")
+		}
+		return nil
+	}, DeadLetter{Publisher: p, Exchange: "dlx"}, retry.Delay(0))
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.Empty(t, p.published)
+}
+
+func TestHandleDeadLetter(t *testing.T) {
+	p := &publisher{}
+	d := amqp.Delivery{
+		Exchange:   "orders",
+		RoutingKey: "orders.created",
+		Headers:    amqp.Table{"tenant": "a"},
+		Body:       []byte("body"),
+	}
+	err := Handle(context.Background(), d, func(ctx context.Context, d amqp.Delivery) error {
+		return errors.New("test")
+	}, DeadLetter{Publisher: p, Exchange: "dlx"}, retry.Attempts(2), retry.Delay(0))
+
+	var dead *DeadLetteredError
+	assert.ErrorAs(t, err, &dead)
+	assert.Len(t, dead.Err.(retry.Error), 2)
+
+	assert.Len(t, p.published, 1)
+	assert.Equal(t, "dlx", p.published[0].exchange)
+	assert.Equal(t, "orders.created", p.published[0].key)
+	assert.Equal(t, []byte("body"), p.published[0].msg.Body)
+	assert.Equal(t, amqp.Table{
+		"tenant":                 "a",
+		HeaderAttempts:           int32(2),
+		HeaderErrors:             []interface{}{"test", "test"},
+		HeaderOriginalExchange:   "orders",
+		HeaderOriginalRoutingKey: "orders.created",
+	}, p.published[0].msg.Headers)
+	assert.NoError(t, p.published[0].msg.Headers.Validate())
+}
+
+func TestHandlePublishFailed(t *testing.T) {
+	pubErr := errors.New("channel closed")
+	p := &publisher{err: pubErr}
+	err := Handle(context.Background(), amqp.Delivery{}, func(ctx context.Context, d amqp.Delivery) error {
+		return retry.Unrecoverable(errors.New("test"))
+	}, DeadLetter{Publisher: p, Exchange: "dlx", RoutingKey: "dead"})
+
+	assert.ErrorIs(t, err, pubErr)
+	var dead *DeadLetteredError
+	assert.False(t, errors.As(err, &dead))
+	assert.Equal(t, "dead", p.published[0].key)
+}
+
+func TestHandleCancelled(t *testing.T) {
+	p := &publisher{}
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Handle(ctx, amqp.Delivery{}, func(ctx context.Context, d amqp.Delivery) error {
+		cancel()
+		return errors.New("test")
+	}, DeadLetter{Publisher: p, Exchange: "dlx"}, retry.Delay(0))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, p.published)
+}
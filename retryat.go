@@ -0,0 +1,87 @@
+package retry
+
+import (
+	"errors"
+	"time"
+)
+
+// NextAttemptAt returns the time of the next attempt requested by `err` and whether there is one.
+// The time is taken from the first error in the chain implementing `NextAttemptAt() time.Time`,
+// e.g. a quota system saying "retry after 14:00 UTC"; the zero time is ignored.
+// The delay until the time is used like a `RetryAfter` hint: instead of the DelayType and capped by MaxDelay.
+func NextAttemptAt(err error) (time.Time, bool) {
+	var hint interface{ NextAttemptAt() time.Time }
+	if !errors.As(err, &hint) {
+		return time.Time{}, false
+	}
+
+	at := hint.NextAttemptAt()
+	if at.IsZero() {
+		return time.Time{}, false
+	}
+	return at, true
+}
+
+// RetryAt wraps the error with the time of the next attempt, see `NextAttemptAt`.
+// RetryAt(nil, at) returns nil.
+//
+//	retry.Do(
+//		func() error {
+//			err := client.Call()
+//			if quota, ok := err.(*QuotaError); ok {
+//				return retry.RetryAt(err, quota.ResetsAt)
+//			}
+//			return err
+//		},
+//	)
+func RetryAt(err error, at time.Time) error {
+	if err == nil {
+		return nil
+	}
+	return &retryAtError{err: err, at: at}
+}
+
+type retryAtError struct {
+	err error
+	at  time.Time
+}
+
+func (e *retryAtError) Error() string {
+	return e.err.Error()
+}
+
+func (e *retryAtError) Unwrap() error {
+	return e.err
+}
+
+func (e *retryAtError) NextAttemptAt() time.Time {
+	return e.at
+}
+
+// AbsoluteDelay is a DelayType waiting until the time returned by next,
+// for classifiers which know when to retry rather than how long to wait.
+// Times in the past retry immediately; when next returns the zero time, FixedDelay is used.
+//
+//	retry.Do(
+//		func() error { ... },
+//		retry.DelayType(retry.AbsoluteDelay(func(n uint, err error) time.Time {
+//			return quota.ResetsAt()
+//		})),
+//	)
+func AbsoluteDelay(next func(n uint, err error) time.Time) DelayTypeFunc {
+	return func(n uint, err error, config *Config) time.Duration {
+		at := next(n, err)
+		if at.IsZero() {
+			return FixedDelay(n, err, config)
+		}
+		return untilTime(config, at)
+	}
+}
+
+// untilTime returns the delay until the time by the Clock of the config, 0 for times in the past
+func untilTime(config *Config, at time.Time) time.Duration {
+	if d := at.Sub(config.clock.Now()); d > 0 {
+		return d
+	}
+	return 0
+}
@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAt(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 13, 30, 0, 0, time.UTC)}
+	quotaReset := time.Date(2024, time.March, 6, 14, 0, 0, 0, time.UTC)
+	testErr := errors.New("quota exceeded")
+
+	var count int
+	err := Do(
+		func() error {
+			count++
+			if count == 1 {
+				return RetryAt(testErr, quotaReset)
+			}
+			return nil
+		},
+		WithClock(clock),
+		MaxDelay(time.Hour),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{30 * time.Minute}, clock.delays)
+
+	at, ok := NextAttemptAt(RetryAt(testErr, quotaReset))
+	assert.True(t, ok)
+	assert.Equal(t, quotaReset, at)
+	assert.ErrorIs(t, RetryAt(testErr, quotaReset), testErr)
+
+	_, ok = NextAttemptAt(RetryAt(testErr, time.Time{}))
+	assert.False(t, ok)
+	assert.Nil(t, RetryAt(nil, quotaReset))
+}
+
+func TestAbsoluteDelay(t *testing.T) {
+	clock := &fakeClock{now: time.Date(2024, time.March, 6, 13, 30, 0, 0, time.UTC)}
+	at := []time.Time{
+		time.Date(2024, time.March, 6, 14, 0, 0, 0, time.UTC),
+		time.Date(2024, time.March, 6, 12, 0, 0, 0, time.UTC), // in the past
+		{},
+	}
+
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(4),
+		WithClock(clock),
+		Delay(time.Second),
+		DelayType(AbsoluteDelay(func(n uint, err error) time.Time { return at[n] })),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, []time.Duration{30 * time.Minute, 0, time.Second}, clock.delays)
+}
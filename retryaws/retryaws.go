@@ -0,0 +1,115 @@
+/*
+Package retryaws provides AWS SDK error classifiers for github.com/avast/retry-go
+
+It does not depend on the AWS SDK, errors are recognized by the interfaces of smithy-go
+(`smithy.APIError`, `smithyhttp.ResponseError`), so it works with all AWS SDK for Go v2 service clients.
+
+retry an AWS call only on throttling and server errors:
+
+	err := retry.Do(
+		func() error {
+			_, err := client.PutObject(ctx, input)
+			return err
+		},
+		retry.RetryIf(retryaws.IsRetryable),
+	)
+
+The service clients retry on their own too, consider `aws.NopRetryer` (or lower `MaxAttempts`)
+when retrying with this package, so the attempts don't multiply.
+*/
+package retryaws
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// ThrottleCodes are the API error codes recognized as throttling by `IsThrottle`,
+// the same as recognized by the retryer of AWS SDK v2
+var ThrottleCodes = []string{
+	"Throttling",
+	"ThrottlingException",
+	"ThrottledException",
+	"RequestThrottledException",
+	"TooManyRequestsException",
+	"ProvisionedThroughputExceededException",
+	"TransactionInProgressException",
+	"RequestLimitExceeded",
+	"BandwidthLimitExceeded",
+	"LimitExceededException",
+	"RequestThrottled",
+	"SlowDown",
+	"PriorRequestNotComplete",
+	"EC2ThrottledException",
+}
+
+// TransientCodes are the API error codes of transient failures recognized by `IsRetryable`
+var TransientCodes = []string{
+	"RequestTimeout",
+	"RequestTimeoutException",
+	"InternalError",
+	"ServiceUnavailable",
+}
+
+// ServerErrorStatuses are the HTTP statuses of responses recognized by `IsServerError`
+var ServerErrorStatuses = []int{500, 502, 503, 504}
+
+// apiError is implemented by smithy.APIError
+type apiError interface {
+	ErrorCode() string
+}
+
+// responseError is implemented by smithyhttp.ResponseError and awshttp.ResponseError
+type responseError interface {
+	HTTPStatusCode() int
+}
+
+// IsRetryable checks if the error is a throttling error, a server error or an API error
+// with one of `TransientCodes`, i.e. a condition the service recommends retrying.
+// Unrecoverable errors and context errors are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || retry.IsUnrecoverable(err) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return IsThrottle(err) || IsServerError(err) || hasCode(err, TransientCodes)
+}
+
+// IsThrottle checks if the error (or any error it wraps) is an API error with one of `ThrottleCodes`
+func IsThrottle(err error) bool {
+	return hasCode(err, ThrottleCodes)
+}
+
+// IsServerError checks if the error (or any error it wraps) is an HTTP response error
+// with one of `ServerErrorStatuses`
+func IsServerError(err error) bool {
+	var respErr responseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	status := respErr.HTTPStatusCode()
+	for _, s := range ServerErrorStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCode(err error, codes []string) bool {
+	var apiErr apiError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	code := apiErr.ErrorCode()
+	for _, c := range codes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
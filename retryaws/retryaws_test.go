@@ -0,0 +1,68 @@
+package retryaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// genericAPIError mimics smithy.GenericAPIError
+type genericAPIError struct {
+	Code string
+}
+
+func (e *genericAPIError) Error() string     { return "api error " + e.Code }
+func (e *genericAPIError) ErrorCode() string { return e.Code }
+
+// responseErr mimics smithyhttp.ResponseError wrapping the API error of the response
+type responseErr struct {
+	status int
+	err    error
+}
+
+func (e *responseErr) Error() string {
+	return fmt.Sprintf("https response error StatusCode: %d, %s", e.status, e.err)
+}
+func (e *responseErr) HTTPStatusCode() int { return e.status }
+func (e *responseErr) Unwrap() error       { return e.err }
+
+// operationErr mimics smithy.OperationError
+type operationErr struct {
+	err error
+}
+
+func (e *operationErr) Error() string { return "operation error S3: PutObject, " + e.err.Error() }
+func (e *operationErr) Unwrap() error { return e.err }
+
+func TestIsRetryable(t *testing.T) {
+	for _, err := range []error{
+		&operationErr{&responseErr{status: 400, err: &genericAPIError{Code: "ThrottlingException"}}},
+		&operationErr{&responseErr{status: 503, err: &genericAPIError{Code: "SlowDown"}}},
+		&operationErr{&responseErr{status: 500, err: &genericAPIError{Code: "Unknown"}}},
+		&genericAPIError{Code: "RequestLimitExceeded"},
+		&genericAPIError{Code: "RequestTimeout"},
+	} {
+		assert.True(t, IsRetryable(err), err.Error())
+	}
+
+	for _, err := range []error{
+		nil,
+		errors.New("test"),
+		&operationErr{&responseErr{status: 404, err: &genericAPIError{Code: "NoSuchKey"}}},
+		&operationErr{&responseErr{status: 403, err: &genericAPIError{Code: "AccessDenied"}}},
+		retry.Unrecoverable(&genericAPIError{Code: "Throttling"}),
+		&operationErr{context.Canceled},
+	} {
+		assert.False(t, IsRetryable(err), fmt.Sprint(err))
+	}
+}
+
+func TestIsThrottle(t *testing.T) {
+	assert.True(t, IsThrottle(&operationErr{&genericAPIError{Code: "ProvisionedThroughputExceededException"}}))
+	assert.False(t, IsThrottle(&responseErr{status: 503, err: errors.New("test")}))
+	assert.True(t, IsServerError(&responseErr{status: 503, err: errors.New("test")}))
+}
@@ -0,0 +1,131 @@
+/*
+Package retryexec runs external commands with github.com/avast/retry-go
+
+re-run a flaky command while it exits with status 75 (EX_TEMPFAIL):
+
+	recorder := &retry.Recorder{}
+	output, err := retryexec.Run(ctx,
+		func() *exec.Cmd {
+			return exec.Command("rsync", "-a", src, dst)
+		},
+		retry.RetryIf(retryexec.RetryIfExitCode(75)),
+		retry.WithRecorder(recorder),
+	)
+	for _, attempt := range recorder.Attempts() {
+		var exitErr *retryexec.ExitError
+		if errors.As(attempt.Err, &exitErr) {
+			log.Printf("#%d exited with %d:\n%s", attempt.Number, exitErr.ExitCode, exitErr.Output)
+		}
+	}
+
+An exec.Cmd can't be started twice, so the command is re-created by the factory for every attempt.
+*/
+package retryexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// ExitError is the error of an attempt of the command which exited unsuccessfully.
+// It carries the combined output of the attempt, so it is kept in the records of `retry.WithRecorder`
+// and in the `retry.Error` of the failed retry.
+type ExitError struct {
+	// ExitCode of the process, -1 when it was terminated by a signal
+	ExitCode int
+	// Output is the combined standard output and standard error of the attempt,
+	// nil when the factory set Stdout or Stderr of the command
+	Output []byte
+	// Err is the *exec.ExitError of the attempt
+	Err error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Run runs the command created by cmdFactory, re-creating and re-running it when it exits unsuccessfully
+// (every non-zero exit code is retried by default, see `RetryIfExitCode`).
+// Commands which can't be started (e.g. the executable is not found) are not retried.
+// When the context is done, the running command is killed.
+//
+// The combined standard output and standard error of every attempt is captured unless the factory sets
+// Stdout or Stderr of the command; Run returns the output of the successful attempt.
+func Run(ctx context.Context, cmdFactory func() *exec.Cmd, opts ...retry.Option) ([]byte, error) {
+	runOpts := make([]retry.Option, 0, len(opts)+1)
+	runOpts = append(runOpts, opts...)
+	runOpts = append(runOpts, retry.Context(ctx))
+
+	return retry.DoWithData(func() ([]byte, error) {
+		return run(ctx, cmdFactory())
+	}, runOpts...)
+}
+
+// run runs the command once, killing it when the context is done
+func run(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	var output *bytes.Buffer
+	if cmd.Stdout == nil && cmd.Stderr == nil {
+		output = &bytes.Buffer{}
+		cmd.Stdout = output
+		cmd.Stderr = output
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, retry.Unrecoverable(err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+		case <-done:
+		}
+	}()
+	err := cmd.Wait()
+	close(done)
+
+	var out []byte
+	if output != nil {
+		out = output.Bytes()
+	}
+
+	if err == nil {
+		return out, nil
+	}
+	if ctx.Err() != nil {
+		return out, ctx.Err()
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return out, &ExitError{ExitCode: exitErr.ExitCode(), Output: out, Err: err}
+	}
+	return out, err
+}
+
+// RetryIfExitCode returns a RetryIfFunc which retries attempts of `Run` only when the command exited with one of the codes
+// (use -1 for processes terminated by a signal). Other errors are classified by `retry.IsRetryable`.
+func RetryIfExitCode(codes ...int) retry.RetryIfFunc {
+	retryable := make(map[int]struct{}, len(codes))
+	for _, code := range codes {
+		retryable[code] = struct{}{}
+	}
+
+	return func(err error) bool {
+		var exitErr *ExitError
+		if !retry.IsRecoverable(err) || !errors.As(err, &exitErr) {
+			return retry.IsRetryable(err)
+		}
+
+		_, ok := retryable[exitErr.ExitCode]
+		return ok
+	}
+}
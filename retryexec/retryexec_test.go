@@ -0,0 +1,76 @@
+//go:build !windows && !plan9
+
+package retryexec
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "counter")
+	// fails with 75 twice, then succeeds
+	script := fmt.Sprintf(`echo x >> %[1]s; n=$(wc -l < %[1]s); echo "attempt $n"; echo oops >&2; [ $n -ge 3 ] || exit 75`, counter)
+
+	recorder := &retry.Recorder{}
+	output, err := Run(context.Background(),
+		func() *exec.Cmd { return exec.Command("sh", "-c", script) },
+		retry.RetryIf(RetryIfExitCode(75)),
+		retry.WithRecorder(recorder),
+		retry.Delay(0),
+		retry.MaxJitter(0),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "attempt 3\noops\n", string(output))
+
+	attempts := recorder.Attempts()
+	if assert.Len(t, attempts, 3) {
+		var exitErr *ExitError
+		if assert.ErrorAs(t, attempts[1].Err, &exitErr) {
+			assert.Equal(t, 75, exitErr.ExitCode)
+			assert.Equal(t, "attempt 2\noops\n", string(exitErr.Output))
+			assert.EqualError(t, exitErr, "exit status 75")
+		}
+		assert.NoError(t, attempts[2].Err)
+	}
+}
+
+func TestRunNotRetryable(t *testing.T) {
+	var count int
+	_, err := Run(context.Background(),
+		func() *exec.Cmd {
+			count++
+			return exec.Command("sh", "-c", "exit 2")
+		},
+		retry.RetryIf(RetryIfExitCode(75)),
+	)
+	var exitErr *ExitError
+	assert.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 2, exitErr.ExitCode)
+	assert.Equal(t, 1, count)
+
+	count = 0
+	_, err = Run(context.Background(), func() *exec.Cmd {
+		count++
+		return exec.Command(filepath.Join(t.TempDir(), "missing"))
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+}
+
+func TestRunContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := Run(ctx, func() *exec.Cmd { return exec.Command("sleep", "10") })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
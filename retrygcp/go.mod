@@ -0,0 +1,21 @@
+module github.com/avast/retry-go/v4/retrygcp
+
+go 1.19
+
+require (
+	github.com/avast/retry-go/v4 v4.5.0
+	github.com/stretchr/testify v1.8.4
+	google.golang.org/api v0.126.0
+	google.golang.org/grpc v1.56.3
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20230530153820-e85fd2cbaebc // indirect
+	google.golang.org/protobuf v1.30.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/avast/retry-go/v4 => ../
@@ -0,0 +1,75 @@
+/*
+Package retrygcp provides Google Cloud API error classifiers for github.com/avast/retry-go
+
+It is a separate module, so the core package does not depend on the Google API client libraries.
+It understands both `*googleapi.Error` of REST clients (google.golang.org/api)
+and gRPC status errors of Cloud client libraries (including `*apierror.APIError`).
+
+retry a Google Cloud call only on transient errors:
+
+	err := retry.Do(
+		func() error {
+			_, err := bucket.Object(name).Attrs(ctx)
+			return err
+		},
+		retry.RetryIf(retrygcp.IsRetryable),
+	)
+*/
+package retrygcp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/avast/retry-go/v4"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryableHTTPCodes are the HTTP status codes of `*googleapi.Error` recognized by `IsRetryable`
+var RetryableHTTPCodes = []int{429, 500, 502, 503, 504}
+
+// RetryableCodes are the gRPC status codes recognized by `IsRetryable`
+//
+// codes.DeadlineExceeded is not included because the server may have already
+// applied the request, as in retrygrpc.
+var RetryableCodes = []codes.Code{
+	codes.Unavailable,
+	codes.ResourceExhausted,
+	codes.Internal,
+}
+
+// IsRetryable checks if the error is a `*googleapi.Error` with one of `RetryableHTTPCodes`
+// or carries a gRPC status with one of `RetryableCodes`.
+// Unrecoverable errors and context errors are not retryable.
+func IsRetryable(err error) bool {
+	if err == nil || retry.IsUnrecoverable(err) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		for _, code := range RetryableHTTPCodes {
+			if apiErr.Code == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return false
+	}
+	code := grpcErr.GRPCStatus().Code()
+	for _, c := range RetryableCodes {
+		if code == c {
+			return true
+		}
+	}
+	return false
+}
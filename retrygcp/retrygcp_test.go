@@ -0,0 +1,37 @@
+package retrygcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsRetryable(t *testing.T) {
+	for _, err := range []error{
+		&googleapi.Error{Code: 429},
+		fmt.Errorf("get object: %w", &googleapi.Error{Code: 503}),
+		status.Error(codes.Unavailable, "test"),
+		fmt.Errorf("publish: %w", status.Error(codes.ResourceExhausted, "test")),
+	} {
+		assert.True(t, IsRetryable(err), err.Error())
+	}
+
+	for _, err := range []error{
+		nil,
+		errors.New("test"),
+		&googleapi.Error{Code: 404},
+		status.Error(codes.NotFound, "test"),
+		status.Error(codes.DeadlineExceeded, "test"),
+		retry.Unrecoverable(&googleapi.Error{Code: 503}),
+		context.Canceled,
+	} {
+		assert.False(t, IsRetryable(err), fmt.Sprint(err))
+	}
+}
@@ -0,0 +1,211 @@
+/*
+Package retrygrpc provides gRPC helpers for github.com/avast/retry-go
+
+It is a separate module, so the core package does not depend on gRPC.
+
+retry a gRPC call only on transient status codes:
+
+	err := retry.Do(
+		func() error {
+			_, err := client.Get(ctx, req)
+			return err
+		},
+		retry.RetryIf(retrygrpc.IsRetryableCode()),
+	)
+
+retry unary calls of a client connection, honoring the pushback of the server:
+
+	conn, err := grpc.Dial(addr,
+		grpc.WithUnaryInterceptor(retrygrpc.UnaryClientInterceptor(retry.Attempts(5))),
+	)
+*/
+package retrygrpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// PushbackKey is the trailer of the gRPC retry spec by which the server tells
+// how long to wait before the next attempt (in milliseconds), or not to retry at all (negative).
+const PushbackKey = "grpc-retry-pushback-ms"
+
+// DefaultRetryableCodes are used by `IsRetryableCode` when no codes are given.
+//
+// codes.DeadlineExceeded is not included because the server may have already
+// applied the request; pass it explicitly for idempotent calls.
+var DefaultRetryableCodes = []codes.Code{
+	codes.Unavailable,
+	codes.ResourceExhausted,
+}
+
+// IsRetryableCode returns a RetryIfFunc which retries errors carrying one of the given
+// gRPC status codes (`DefaultRetryableCodes` if none are given).
+// Errors without a gRPC status (e.g. of other work done by the retried function) are classified by `retry.IsRetryable`,
+// as `retry.RetryIfHTTPStatus` does for errors without an HTTP status; unrecoverable errors are not retried.
+func IsRetryableCode(retryable ...codes.Code) retry.RetryIfFunc {
+	if len(retryable) == 0 {
+		retryable = DefaultRetryableCodes
+	}
+	set := make(map[codes.Code]struct{}, len(retryable))
+	for _, code := range retryable {
+		set[code] = struct{}{}
+	}
+
+	return func(err error) bool {
+		if !retry.IsRecoverable(err) {
+			return false
+		}
+
+		s, ok := status.FromError(err)
+		if !ok {
+			return retry.IsRetryable(err)
+		}
+
+		_, ok = set[s.Code()]
+		return ok
+	}
+}
+
+// Pushback returns the pushback of the server from the trailer of a call, see `PushbackKey`.
+// ok is false when the trailer carries no (valid) pushback; a negative delay means the call should not be retried.
+func Pushback(trailer metadata.MD) (d time.Duration, ok bool) {
+	values := trailer.Get(PushbackKey)
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	ms, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return time.Duration(ms) * time.Millisecond, true
+}
+
+// WithPushback annotates the error of a call with the pushback of the server from the trailer:
+// the error requests the delay (see `retry.RetryAfter`), or is `retry.Unrecoverable` for a negative pushback.
+// The error is returned as is when there is no pushback.
+//
+//	var trailer metadata.MD
+//	err := retry.Do(
+//		func() error {
+//			_, err := client.Get(ctx, req, grpc.Trailer(&trailer))
+//			return retrygrpc.WithPushback(err, trailer)
+//		},
+//		retry.RetryIf(retrygrpc.IsRetryableCode()),
+//	)
+func WithPushback(err error, trailer metadata.MD) error {
+	if err == nil {
+		return nil
+	}
+
+	d, ok := Pushback(trailer)
+	switch {
+	case !ok:
+		return err
+	case d < 0:
+		return retry.Unrecoverable(err)
+	default:
+		return &pushbackError{err: err, pushback: d}
+	}
+}
+
+// pushbackError is the error of a call with a pushback of the server
+type pushbackError struct {
+	err      error
+	pushback time.Duration
+}
+
+func (e *pushbackError) Error() string {
+	return e.err.Error()
+}
+
+func (e *pushbackError) Unwrap() error {
+	return e.err
+}
+
+// GRPCStatus returns the status of the call, so status.FromError works with the error
+func (e *pushbackError) GRPCStatus() *status.Status {
+	s, _ := status.FromError(e.err)
+	return s
+}
+
+// RetryAfter returns the pushback, see `retry.RetryAfter`
+func (e *pushbackError) RetryAfter() time.Duration {
+	return e.pushback
+}
+
+// UnaryClientInterceptor retries unary calls with the given options.
+// Errors are retried by `IsRetryableCode` unless RetryIf is set, LastErrorOnly is enabled by default
+// and the context of the call is set as the retry `Context`.
+// The pushback of the server is honored (see `WithPushback`).
+func UnaryClientInterceptor(opts ...retry.Option) grpc.UnaryClientInterceptor {
+	return PerMethodUnaryClientInterceptor(nil, opts...)
+}
+
+// MethodOptions returns the options of calls of the full method (e.g. "/payments.Payments/Charge"),
+// ok is false for methods without their own options
+type MethodOptions func(method string) (opts []retry.Option, ok bool)
+
+// ByMethod returns MethodOptions looking up the full method name in the map,
+// keys ending with "/" (e.g. "/catalog.Catalog/") match all methods of the service
+func ByMethod(options map[string][]retry.Option) MethodOptions {
+	return func(method string) ([]retry.Option, bool) {
+		if opts, ok := options[method]; ok {
+			return opts, true
+		}
+		if i := strings.LastIndex(method, "/"); i >= 0 {
+			opts, ok := options[method[:i+1]]
+			return opts, ok
+		}
+		return nil, false
+	}
+}
+
+// PerMethodUnaryClientInterceptor is UnaryClientInterceptor with options of every call
+// extended by the options of its method, so policies of all methods are declared in one interceptor:
+//
+//	retrygrpc.PerMethodUnaryClientInterceptor(
+//		retrygrpc.ByMethod(map[string][]retry.Option{
+//			"/payments.Payments/Charge": {retry.Attempts(1)}, // never retried
+//			"/catalog.Catalog/":         {retry.Attempts(10), retry.Delay(10 * time.Millisecond)},
+//		}),
+//		retry.Attempts(3),
+//	)
+func PerMethodUnaryClientInterceptor(methods MethodOptions, opts ...retry.Option) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, callOpts ...grpc.CallOption) error {
+		var methodOpts []retry.Option
+		if methods != nil {
+			methodOpts, _ = methods(method)
+		}
+
+		options := make([]retry.Option, 0, len(opts)+len(methodOpts)+3)
+		options = append(options, retry.RetryIf(IsRetryableCode()), retry.LastErrorOnly(true))
+		options = append(options, opts...)
+		options = append(options, methodOpts...)
+		options = append(options, retry.Context(ctx))
+
+		err := retry.Do(func() error {
+			var trailer metadata.MD
+			attemptOpts := make([]grpc.CallOption, 0, len(callOpts)+1)
+			attemptOpts = append(attemptOpts, callOpts...)
+			attemptOpts = append(attemptOpts, grpc.Trailer(&trailer))
+
+			return WithPushback(invoker(ctx, method, req, reply, cc, attemptOpts...), trailer)
+		}, options...)
+
+		// only the last error is unwrapped, errors of all attempts (LastErrorOnly(false)) are kept as they are
+		if pushbackErr, ok := err.(*pushbackError); ok {
+			return pushbackErr.err
+		}
+		return err
+	}
+}
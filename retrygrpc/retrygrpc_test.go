@@ -0,0 +1,142 @@
+package retrygrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestIsRetryableCode(t *testing.T) {
+	defaults := IsRetryableCode()
+	assert.True(t, defaults(status.Error(codes.Unavailable, "unavailable")))
+	assert.True(t, defaults(status.Error(codes.ResourceExhausted, "exhausted")))
+	assert.False(t, defaults(status.Error(codes.DeadlineExceeded, "deadline")))
+	assert.False(t, defaults(status.Error(codes.InvalidArgument, "invalid")))
+	assert.False(t, defaults(retry.Unrecoverable(status.Error(codes.Unavailable, "unavailable"))))
+	assert.True(t, defaults(errors.New("plain")), "errors without a status are classified by retry.IsRetryable")
+	assert.False(t, defaults(retry.Unrecoverable(errors.New("plain"))))
+	assert.True(t, defaults(fmt.Errorf("wrap: %w", status.Error(codes.Unavailable, "unavailable"))))
+
+	withDeadline := IsRetryableCode(codes.Unavailable, codes.DeadlineExceeded)
+	assert.True(t, withDeadline(status.Error(codes.DeadlineExceeded, "deadline")))
+	assert.False(t, withDeadline(status.Error(codes.ResourceExhausted, "exhausted")))
+}
+
+func TestIsRetryableCodeDo(t *testing.T) {
+	attempts := 0
+	err := retry.Do(
+		func() error {
+			attempts++
+			if attempts < 3 {
+				return status.Error(codes.Unavailable, "unavailable")
+			}
+			return status.Error(codes.NotFound, "not found")
+		},
+		retry.RetryIf(IsRetryableCode()),
+		retry.Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestPushback(t *testing.T) {
+	d, ok := Pushback(metadata.Pairs(PushbackKey, "250"))
+	assert.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, d)
+
+	d, ok = Pushback(metadata.Pairs(PushbackKey, "-1"))
+	assert.True(t, ok)
+	assert.Less(t, d, time.Duration(0))
+
+	_, ok = Pushback(metadata.Pairs(PushbackKey, "soon"))
+	assert.False(t, ok)
+	_, ok = Pushback(nil)
+	assert.False(t, ok)
+
+	unavailable := status.Error(codes.Unavailable, "unavailable")
+	err := WithPushback(unavailable, metadata.Pairs(PushbackKey, "250"))
+	d, ok = retry.RetryAfter(err)
+	assert.True(t, ok)
+	assert.Equal(t, 250*time.Millisecond, d)
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+
+	assert.True(t, retry.IsUnrecoverable(WithPushback(unavailable, metadata.Pairs(PushbackKey, "-1"))))
+	assert.Equal(t, unavailable, WithPushback(unavailable, nil))
+	assert.NoError(t, WithPushback(nil, metadata.Pairs(PushbackKey, "250")))
+}
+
+// pushbackInvoker fails with Unavailable, sending the pushbacks as trailers one by one
+func pushbackInvoker(calls *int, pushbacks ...string) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		if *calls < len(pushbacks) {
+			for _, opt := range opts {
+				if trailer, ok := opt.(grpc.TrailerCallOption); ok {
+					*trailer.TrailerAddr = metadata.Pairs(PushbackKey, pushbacks[*calls])
+				}
+			}
+		}
+		*calls++
+		return status.Error(codes.Unavailable, "unavailable")
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	var delays []time.Duration
+	interceptor := UnaryClientInterceptor(
+		retry.Attempts(3),
+		retry.Delay(time.Millisecond),
+		retry.DelayType(retry.FixedDelay),
+		retry.OnDelay(func(n uint, d time.Duration) { delays = append(delays, d) }),
+	)
+
+	calls := 0
+	err := interceptor(context.Background(), "/catalog/Get", nil, nil, nil, pushbackInvoker(&calls, "2"))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{2 * time.Millisecond, time.Millisecond}, delays)
+
+	calls = 0
+	err = interceptor(context.Background(), "/catalog/Get", nil, nil, nil, pushbackInvoker(&calls, "-1"))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.False(t, retry.IsUnrecoverable(err))
+	assert.Equal(t, 1, calls, "negative pushback stops retries")
+
+	interceptor = UnaryClientInterceptor(retry.Attempts(3), retry.Delay(time.Millisecond), retry.LastErrorOnly(false))
+	calls = 0
+	err = interceptor(context.Background(), "/catalog/Get", nil, nil, nil, pushbackInvoker(&calls, "1"))
+	var retryErr retry.Error
+	assert.ErrorAs(t, err, &retryErr, "errors of all attempts are returned")
+	assert.Len(t, retryErr, 3)
+	assert.Equal(t, codes.Unavailable, status.Code(retryErr[0]))
+}
+
+func TestPerMethodUnaryClientInterceptor(t *testing.T) {
+	interceptor := PerMethodUnaryClientInterceptor(
+		ByMethod(map[string][]retry.Option{
+			"/payments.Payments/Charge": {retry.Attempts(1)},
+			"/catalog.Catalog/":         {retry.Attempts(5)},
+		}),
+		retry.Attempts(3),
+		retry.Delay(time.Nanosecond),
+	)
+
+	for method, expected := range map[string]int{
+		"/payments.Payments/Charge": 1,
+		"/payments.Payments/Refund": 3,
+		"/catalog.Catalog/Get":      5,
+	} {
+		calls := 0
+		err := interceptor(context.Background(), method, nil, nil, nil, pushbackInvoker(&calls))
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+		assert.Equal(t, expected, calls, method)
+	}
+}
@@ -0,0 +1,365 @@
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// CheckRetry decides whether to retry after a request, with the signature of go-retryablehttp.
+// A non-nil error returned by the last call is returned by `Client.Do` instead of the error of the request.
+type CheckRetry func(ctx context.Context, resp *http.Response, err error) (bool, error)
+
+// Backoff returns the delay before the next attempt, with the signature of go-retryablehttp.
+// attemptNum starts from 0, resp is nil when the request failed.
+type Backoff func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration
+
+// ErrorHandler is called when all attempts fail, with the signature of go-retryablehttp.
+// Its results are returned by `Client.Do`; without it the response is closed and an error is returned.
+type ErrorHandler func(resp *http.Response, err error, numTries int) (*http.Response, error)
+
+// Client is a drop-in replacement of the Client of github.com/hashicorp/go-retryablehttp
+// retrying requests with github.com/avast/retry-go.
+// Logger and the log hooks of go-retryablehttp are not supported, use `retry.OnRetry` in Options instead.
+//
+//	client := retryhttp.NewClient()
+//	client.RetryMax = 3
+//	resp, err := client.Get(url)
+type Client struct {
+	// HTTPClient makes the requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+
+	// RetryWaitMin is the minimal delay passed to Backoff
+	RetryWaitMin time.Duration
+	// RetryWaitMax is the maximal delay passed to Backoff
+	RetryWaitMax time.Duration
+	// RetryMax is the maximal count of retries
+	RetryMax int
+
+	// CheckRetry decides whether to retry, `DefaultRetryPolicy` if nil
+	CheckRetry CheckRetry
+	// Backoff returns the delays between attempts, `DefaultBackoff` if nil
+	Backoff Backoff
+	// ErrorHandler is called when all attempts fail if not nil
+	ErrorHandler ErrorHandler
+
+	// Options of the retry of every request, e.g. `retry.OnRetry` or `retry.WithTimer`.
+	// Attempts and delays are set by the fields above.
+	Options []retry.Option
+}
+
+// NewClient returns a Client with the defaults of go-retryablehttp
+func NewClient() *Client {
+	return &Client{
+		HTTPClient:   &http.Client{},
+		RetryWaitMin: 1 * time.Second,
+		RetryWaitMax: 30 * time.Second,
+		RetryMax:     4,
+		CheckRetry:   DefaultRetryPolicy,
+		Backoff:      DefaultBackoff,
+	}
+}
+
+// Request wraps http.Request with a body which can be read again by every attempt
+type Request struct {
+	*http.Request
+
+	body func() (io.Reader, error)
+}
+
+// NewRequest creates a Request with a body of one of the types accepted by go-retryablehttp:
+// []byte, string, *bytes.Buffer, *bytes.Reader, *strings.Reader, func() (io.Reader, error) or io.Reader
+// (read into memory), or nil.
+func NewRequest(method, url string, rawBody interface{}) (*Request, error) {
+	return NewRequestWithContext(context.Background(), method, url, rawBody)
+}
+
+// NewRequestWithContext is NewRequest with a context
+func NewRequestWithContext(ctx context.Context, method, url string, rawBody interface{}) (*Request, error) {
+	body, contentLength, err := getBody(rawBody)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	httpReq.ContentLength = contentLength
+
+	return &Request{Request: httpReq, body: body}, nil
+}
+
+// FromRequest wraps http.Request, its body is read into memory unless it can be rewound by GetBody
+func FromRequest(r *http.Request) (*Request, error) {
+	req := &Request{Request: r}
+	if !hasBody(r) {
+		return req, nil
+	}
+
+	if r.GetBody != nil {
+		req.body = func() (io.Reader, error) {
+			return r.GetBody()
+		}
+		return req, nil
+	}
+
+	body, contentLength, err := getBody(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.body = body
+	req.ContentLength = contentLength
+	return req, nil
+}
+
+func getBody(rawBody interface{}) (func() (io.Reader, error), int64, error) {
+	switch body := rawBody.(type) {
+	case nil:
+		return nil, 0, nil
+	case func() (io.Reader, error):
+		return body, -1, nil
+	case []byte:
+		return func() (io.Reader, error) { return bytes.NewReader(body), nil }, int64(len(body)), nil
+	case string:
+		return func() (io.Reader, error) { return strings.NewReader(body), nil }, int64(len(body)), nil
+	case *bytes.Buffer:
+		buf := body.Bytes()
+		return func() (io.Reader, error) { return bytes.NewReader(buf), nil }, int64(len(buf)), nil
+	case *bytes.Reader:
+		buf := make([]byte, body.Len())
+		_, _ = body.Read(buf)
+		return func() (io.Reader, error) { return bytes.NewReader(buf), nil }, int64(len(buf)), nil
+	case *strings.Reader:
+		buf := make([]byte, body.Len())
+		_, _ = body.Read(buf)
+		return func() (io.Reader, error) { return bytes.NewReader(buf), nil }, int64(len(buf)), nil
+	case io.Reader:
+		buf, err := io.ReadAll(body)
+		if closer, ok := body.(io.Closer); ok {
+			closer.Close()
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		return func() (io.Reader, error) { return bytes.NewReader(buf), nil }, int64(len(buf)), nil
+	default:
+		return nil, 0, fmt.Errorf("retryhttp: cannot handle body of type %T", rawBody)
+	}
+}
+
+// Do sends the request, retrying it as decided by CheckRetry with delays of Backoff
+func (c *Client) Do(req *Request) (*http.Response, error) {
+	checkRetry := c.CheckRetry
+	if checkRetry == nil {
+		checkRetry = DefaultRetryPolicy
+	}
+	backoff := c.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff
+	}
+
+	ctx := req.Context()
+	attempts := c.RetryMax + 1
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var tries int
+	var resp *http.Response
+	var doErr error
+	opts := make([]retry.Option, 0, len(c.Options)+3)
+	opts = append(opts, retry.Attempts(uint(attempts)), retry.LastErrorOnly(true))
+	opts = append(opts, c.Options...)
+	opts = append(opts, retry.Context(ctx))
+	err := retry.Do(func() error {
+		if resp != nil {
+			drain(resp, DefaultMaxDrainBody)
+			resp.Body.Close()
+		}
+		tries++
+
+		if req.body != nil {
+			body, err := req.body()
+			if err != nil {
+				resp, doErr = nil, err
+				return nil
+			}
+			if rc, ok := body.(io.ReadCloser); ok {
+				req.Request.Body = rc
+			} else {
+				req.Request.Body = io.NopCloser(body)
+			}
+		}
+
+		resp, doErr = c.httpClient().Do(req.Request)
+		shouldRetry, checkErr := checkRetry(ctx, resp, doErr)
+		if checkErr != nil {
+			doErr = checkErr
+		}
+		if !shouldRetry {
+			return nil
+		}
+		return &backoffError{delay: backoff(c.RetryWaitMin, c.RetryWaitMax, tries-1, resp), err: doErr}
+	}, opts...)
+
+	if err == nil {
+		return resp, doErr
+	}
+
+	var backoffErr *backoffError
+	if !errors.As(err, &backoffErr) {
+		// the context is done
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return nil, err
+	}
+
+	if c.ErrorHandler != nil {
+		return c.ErrorHandler(resp, doErr, tries)
+	}
+	if resp != nil {
+		resp.Body.Close()
+	}
+	if doErr == nil {
+		return nil, fmt.Errorf("%s %s giving up after %d attempt(s)", req.Method, req.URL, tries)
+	}
+	return nil, fmt.Errorf("%s %s giving up after %d attempt(s): %w", req.Method, req.URL, tries, doErr)
+}
+
+// Get sends a GET request
+func (c *Client) Get(url string) (*http.Response, error) {
+	req, err := NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Head sends a HEAD request
+func (c *Client) Head(url string) (*http.Response, error) {
+	req, err := NewRequest(http.MethodHead, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return c.Do(req)
+}
+
+// Post sends a POST request with the body of a type accepted by NewRequest
+func (c *Client) Post(url, bodyType string, body interface{}) (*http.Response, error) {
+	req, err := NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", bodyType)
+	return c.Do(req)
+}
+
+// PostForm sends a POST request with the URL-encoded form
+func (c *Client) PostForm(url string, data url.Values) (*http.Response, error) {
+	return c.Post(url, "application/x-www-form-urlencoded", data.Encode())
+}
+
+// StandardClient returns http.Client sending requests with the Client
+func (c *Client) StandardClient() *http.Client {
+	return &http.Client{Transport: clientTransport{c}}
+}
+
+func (c *Client) httpClient() *http.Client {
+	if c.HTTPClient == nil {
+		return http.DefaultClient
+	}
+	return c.HTTPClient
+}
+
+type clientTransport struct {
+	client *Client
+}
+
+func (t clientTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the request, Do sets the body of every attempt on the clone
+	req, err := FromRequest(r.Clone(r.Context()))
+	if err != nil {
+		return nil, err
+	}
+	return t.client.Do(req)
+}
+
+// DefaultRetryPolicy retries failed requests (except for invalid certificates),
+// responses with status 429 and 5xx except for 501, as go-retryablehttp does.
+func DefaultRetryPolicy(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if err != nil {
+		var unknownAuthority x509.UnknownAuthorityError
+		if errors.As(err, &unknownAuthority) {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		resp.StatusCode == 0 ||
+		(resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented) {
+		return true, fmt.Errorf("unexpected HTTP status %s", resp.Status)
+	}
+	return false, nil
+}
+
+// DefaultBackoff doubles the delay from min up to max with every attempt,
+// responses with status 429 and 503 wait as requested by their Retry-After header.
+func DefaultBackoff(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+	if resp != nil && (resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable) {
+		if d, ok := retry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return d
+		}
+	}
+
+	if attemptNum > 62 {
+		return max
+	}
+	d := min << uint(attemptNum)
+	if d > max || d < min {
+		return max
+	}
+	return d
+}
+
+// backoffError is the error of an attempt to be retried after the delay
+type backoffError struct {
+	delay time.Duration
+	err   error
+}
+
+func (e *backoffError) Error() string {
+	if e.err == nil {
+		return "retryhttp: retrying request"
+	}
+	return e.err.Error()
+}
+
+func (e *backoffError) Unwrap() error {
+	return e.err
+}
+
+// Retryable returns true, the decision was made by CheckRetry already, see `retry.IsRetryable`
+func (e *backoffError) Retryable() bool {
+	return true
+}
+
+// RetryAfter returns the delay of Backoff, see `retry.RetryAfter`
+func (e *backoffError) RetryAfter() time.Duration {
+	return e.delay
+}
@@ -0,0 +1,136 @@
+package retryhttp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	var backoffs []int
+	client := NewClient()
+	client.Backoff = func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+		backoffs = append(backoffs, attemptNum)
+		return 0
+	}
+
+	resp, err := client.Post(server.URL, "text/plain", "body")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, []string{"body", "body", "body"}, bodies)
+	assert.Equal(t, []int{0, 1}, backoffs)
+}
+
+func TestClientGivingUp(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.RetryMax = 2
+	client.RetryWaitMin, client.RetryWaitMax = 0, 0
+	_, err := client.Get(server.URL)
+	assert.EqualError(t, err, "GET "+server.URL+" giving up after 3 attempt(s): unexpected HTTP status 502 Bad Gateway")
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	client.ErrorHandler = func(resp *http.Response, err error, numTries int) (*http.Response, error) {
+		assert.Equal(t, 3, numTries)
+		return resp, nil
+	}
+	resp, err := client.StandardClient().Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	client.CheckRetry = func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+		return false, nil
+	}
+	resp, err = client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, 1, calls)
+}
+
+func TestDefaultBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, DefaultBackoff(time.Second, time.Minute, 0, nil))
+	assert.Equal(t, 8*time.Second, DefaultBackoff(time.Second, time.Minute, 3, nil))
+	assert.Equal(t, time.Minute, DefaultBackoff(time.Second, time.Minute, 10, nil))
+	assert.Equal(t, time.Minute, DefaultBackoff(time.Second, time.Minute, 100, nil))
+
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"5"}}}
+	assert.Equal(t, 5*time.Second, DefaultBackoff(time.Second, time.Minute, 0, resp))
+}
+
+func TestNewRequestBodies(t *testing.T) {
+	for _, rawBody := range []interface{}{
+		"body",
+		[]byte("body"),
+		strings.NewReader("body"),
+		io.NopCloser(strings.NewReader("body")),
+		func() (io.Reader, error) { return strings.NewReader("body"), nil },
+	} {
+		req, err := NewRequest(http.MethodPost, "http://example.com", rawBody)
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			r, _ := req.body()
+			body, _ := io.ReadAll(r)
+			assert.Equal(t, "body", string(body), "%T", rawBody)
+		}
+	}
+
+	_, err := NewRequest(http.MethodPost, "http://example.com", 42)
+	assert.Error(t, err)
+}
+
+func TestClientStandardClientKeepsRequest(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient()
+	client.RetryWaitMin, client.RetryWaitMax = 0, 0
+
+	body := io.NopCloser(strings.NewReader("payload")) // can't be rewound by GetBody
+	req, err := http.NewRequest(http.MethodPost, server.URL, body)
+	assert.NoError(t, err)
+	resp, err := clientTransport{client}.RoundTrip(req)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"payload", "payload"}, bodies)
+	assert.Equal(t, body, req.Body, "the request is not modified")
+	assert.Equal(t, int64(0), req.ContentLength)
+}
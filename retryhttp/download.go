@@ -0,0 +1,152 @@
+package retryhttp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// ErrContentChanged is returned by `Download` when the content changed on the server while resuming it
+var ErrContentChanged = errors.New("retryhttp: content changed while downloading")
+
+// Download writes the content of the URL to w. The requests are retried with the options
+// (on round trip errors and on the statuses retried by `Transport` by default),
+// and when the transfer of the body drops (see `retry.IsTransientIOError`), it is resumed from the last received byte
+// by a Range request instead of restarting it. Both share one retry loop, so the Attempts of the options
+// limit the count of all requests and the hooks are called once per failed request.
+// Servers not supporting ranges send the whole content again, the already received part is skipped then.
+// The ETag (or Last-Modified) of the first response is sent in If-Range, content changed in the meantime
+// fails the download with ErrContentChanged.
+//
+// Errors writing to w are not retried. It returns count of bytes written to w.
+//
+//	f, err := os.Create("image.iso")
+//	...
+//	n, err := retryhttp.Download(ctx, url, f, retry.Attempts(5))
+func Download(ctx context.Context, url string, w io.Writer, opts ...retry.Option) (int64, error) {
+	d := &download{url: url, w: w}
+
+	downloadOpts := make([]retry.Option, 0, len(opts)+2)
+	downloadOpts = append(downloadOpts, retry.LastErrorOnly(true))
+	downloadOpts = append(downloadOpts, opts...)
+	downloadOpts = append(downloadOpts, retry.Context(ctx))
+	err := retry.Do(func() error {
+		return d.attempt(ctx, http.DefaultClient)
+	}, downloadOpts...)
+	return d.written, err
+}
+
+// download is the state of a download between attempts
+type download struct {
+	url       string
+	w         io.Writer
+	written   int64
+	validator string // ETag or Last-Modified of the first response
+}
+
+// attempt requests the rest of the content and writes it
+func (d *download) attempt(ctx context.Context, client *http.Client) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url, nil)
+	if err != nil {
+		return retry.Unrecoverable(err)
+	}
+	if d.written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.written))
+		if d.validator != "" {
+			req.Header.Set("If-Range", d.validator)
+		}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	skip, err := d.skip(resp)
+	if err != nil || skip < 0 {
+		return err
+	}
+	if skip > 0 {
+		if _, err := io.CopyN(io.Discard, resp.Body, skip); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.Copy(writerFunc(func(p []byte) (int, error) {
+		n, err := d.w.Write(p)
+		d.written += int64(n)
+		if err != nil {
+			return n, retry.Unrecoverable(err)
+		}
+		return n, nil
+	}), resp.Body)
+	return err
+}
+
+// skip checks the response, returns count of already written bytes to skip in the body,
+// or -1 when the download is complete
+func (d *download) skip(resp *http.Response) (int64, error) {
+	validator := resp.Header.Get("ETag")
+	if validator == "" || strings.HasPrefix(validator, "W/") {
+		validator = resp.Header.Get("Last-Modified")
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		if d.written > 0 && validator != d.validator {
+			return 0, retry.Unrecoverable(ErrContentChanged)
+		}
+		d.validator = validator
+		return d.written, nil
+	case resp.StatusCode == http.StatusPartialContent && d.written > 0:
+		start, _, ok := contentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != d.written {
+			return 0, retry.Unrecoverable(fmt.Errorf("retryhttp: unexpected Content-Range %q at offset %d", resp.Header.Get("Content-Range"), d.written))
+		}
+		return 0, nil
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && d.written > 0:
+		// the connection dropped right after the last byte
+		if _, size, ok := contentRange(resp.Header.Get("Content-Range")); ok && size == d.written {
+			return -1, nil
+		}
+	}
+	if policy := (&Transport{}).policy(resp.StatusCode); policy.Retry {
+		return 0, newStatusError(resp, policy, 1)
+	}
+	return 0, retry.Unrecoverable(&retry.HTTPError{StatusCode: resp.StatusCode})
+}
+
+// contentRange parses the start and the complete length of the Content-Range header,
+// "bytes 100-199/200" or "bytes */200"
+func contentRange(header string) (start, size int64, ok bool) {
+	rangeSpec, sizeSpec, found := strings.Cut(strings.TrimPrefix(header, "bytes "), "/")
+	if !found {
+		return 0, 0, false
+	}
+	size, err := strconv.ParseInt(sizeSpec, 10, 64)
+	if err != nil {
+		size = -1 // unknown
+	}
+	if rangeSpec == "*" {
+		return -1, size, err == nil
+	}
+	startSpec, _, found := strings.Cut(rangeSpec, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err = strconv.ParseInt(startSpec, 10, 64)
+	return start, size, err == nil
+}
+
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,117 @@
+package retryhttp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownload(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+
+	var requests int32
+	var ranges []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		if atomic.AddInt32(&requests, 1) < 3 {
+			// drop the connection in the middle of the body
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(content[:4000])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	n, err := Download(context.Background(), server.URL, &buf, retry.Delay(0), retry.MaxJitter(0))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(content)), n)
+	assert.Equal(t, content, buf.Bytes())
+	assert.Equal(t, []string{"", "bytes=4000-", "bytes=4000-"}, ranges)
+}
+
+func TestDownloadContentChanged(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) == 1 {
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(content[:4000])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		}
+		w.Header().Set("ETag", `"v2"`)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(content))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	n, err := Download(context.Background(), server.URL, &buf, retry.Delay(0), retry.MaxJitter(0))
+	assert.ErrorIs(t, err, ErrContentChanged)
+	assert.Equal(t, int64(4000), n)
+}
+
+func TestContentRange(t *testing.T) {
+	start, size, ok := contentRange("bytes 100-199/200")
+	assert.True(t, ok)
+	assert.Equal(t, int64(100), start)
+	assert.Equal(t, int64(200), size)
+
+	start, size, ok = contentRange("bytes */200")
+	assert.True(t, ok)
+	assert.Equal(t, int64(-1), start)
+	assert.Equal(t, int64(200), size)
+
+	_, _, ok = contentRange("invalid")
+	assert.False(t, ok)
+}
+
+func TestDownloadAttempts(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789"), 1000)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write(content[:4000])
+			w.(http.Flusher).Flush()
+			panic(http.ErrAbortHandler)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	var retries int
+	var buf bytes.Buffer
+	n, err := Download(context.Background(), server.URL, &buf,
+		retry.Attempts(4), retry.Delay(0), retry.MaxJitter(0),
+		retry.OnRetry(func(n uint, err error) { retries++ }))
+	var httpErr *retry.HTTPError
+	if assert.ErrorAs(t, err, &httpErr) {
+		assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	}
+	assert.Equal(t, int64(4000), n)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&requests), "the attempts limit all requests")
+	assert.Equal(t, 4, retries, "the hooks are called once per request")
+}
@@ -0,0 +1,135 @@
+package retryhttp
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings of the phases of an attempt collected by httptrace.
+// A zero duration means the phase did not happen, e.g. there is no DNS lookup or connect
+// for a reused connection and no TLS handshake for plain HTTP.
+type Timings struct {
+	// DNS is the duration of the DNS lookup
+	DNS time.Duration
+	// Connect is the duration of establishing the TCP connection
+	Connect time.Duration
+	// TLSHandshake is the duration of the TLS handshake
+	TLSHandshake time.Duration
+	// TimeToFirstByte is the duration from the start of the attempt to the first byte of the response
+	TimeToFirstByte time.Duration
+	// ReusedConn reports whether an idle connection was reused
+	ReusedConn bool
+}
+
+// Attempt is a record of a single attempt of a request
+type Attempt struct {
+	// Number of the attempt, starting from 1
+	Number uint
+	// Start is the time the attempt started at
+	Start time.Time
+	// Duration of the attempt until the response headers were received
+	Duration time.Duration
+	// StatusCode of the response, zero when there is none
+	StatusCode int
+	// Err returned by the round trip
+	Err error
+	Timings
+}
+
+// Recorder captures every attempt of the requests made by a Transport,
+// so operators can tell whether retries are caused by connection setup or by server latency.
+type Recorder struct {
+	mu       sync.Mutex
+	attempts []Attempt
+}
+
+// Attempts returns a copy of recorded attempts
+func (r *Recorder) Attempts() []Attempt {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Attempt(nil), r.attempts...)
+}
+
+// Reset clears recorded attempts
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = nil
+}
+
+func (r *Recorder) record(a Attempt) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.attempts = append(r.attempts, a)
+}
+
+// tracer collects Timings of an attempt, the hooks may be called from several goroutines
+type tracer struct {
+	start time.Time
+
+	mu                                 sync.Mutex
+	dnsStart, connectStart, tlsStart   time.Time
+	dns, connect, tls, timeToFirstByte time.Duration
+	reusedConn                         bool
+}
+
+func newTracer() *tracer {
+	return &tracer{start: time.Now()}
+}
+
+// withClientTrace adds the hooks of the tracer to hooks already present in ctx
+func (t *tracer) withClientTrace(ctx context.Context) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.lock(func() { t.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.lock(func() { t.dns = time.Since(t.dnsStart) })
+		},
+		ConnectStart: func(_, _ string) {
+			t.lock(func() {
+				if t.connectStart.IsZero() {
+					t.connectStart = time.Now()
+				}
+			})
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				t.lock(func() { t.connect = time.Since(t.connectStart) })
+			}
+		},
+		TLSHandshakeStart: func() {
+			t.lock(func() { t.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			t.lock(func() { t.tls = time.Since(t.tlsStart) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.lock(func() { t.reusedConn = info.Reused })
+		},
+		GotFirstResponseByte: func() {
+			t.lock(func() { t.timeToFirstByte = time.Since(t.start) })
+		},
+	})
+}
+
+func (t *tracer) lock(f func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	f()
+}
+
+func (t *tracer) timings() Timings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return Timings{
+		DNS:             t.dns,
+		Connect:         t.connect,
+		TLSHandshake:    t.tls,
+		TimeToFirstByte: t.timeToFirstByte,
+		ReusedConn:      t.reusedConn,
+	}
+}
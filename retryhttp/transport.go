@@ -0,0 +1,348 @@
+/*
+Package retryhttp provides an HTTP transport retrying requests with github.com/avast/retry-go
+
+retry requests of an http.Client:
+
+	client := &http.Client{
+		Transport: &retryhttp.Transport{
+			Options: []retry.Option{retry.Attempts(3)},
+		},
+	}
+	resp, err := client.Get(url)
+
+Requests are retried when the round trip fails or the response status is one of `Transport.Statuses`.
+Only requests with idempotent methods (GET, HEAD, OPTIONS, TRACE, PUT and DELETE) are retried by default,
+others (e.g. POST) are retried only with an `Idempotency-Key` header or with `Transport.RetryNonIdempotent`,
+so a retry does not accidentally duplicate side effects.
+When all attempts end with a retryable status, the last response is returned (without an error)
+as it would be without retries. The `Retry-After` header of responses is honored (see `retry.RetryAfter`).
+
+the retry policy may be declared per status code (or class of status codes) in one table:
+
+	transport := &retryhttp.Transport{
+		Policies: map[int]retryhttp.Policy{
+			http.StatusTooManyRequests:    {Retry: true},                // waits as requested by Retry-After
+			http.StatusServiceUnavailable: {Retry: true, MaxRetries: 3}, // backs off up to 3 times
+			http.StatusNotImplemented:     {},                           // never retried
+			5:                             {Retry: true},                // any other 5xx
+		},
+	}
+
+`Client` is a drop-in replacement of the client of github.com/hashicorp/go-retryablehttp
+for migrating without rewriting call sites.
+*/
+package retryhttp
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Transport is an http.RoundTripper retrying requests
+type Transport struct {
+	// Base makes the requests, http.DefaultTransport if nil
+	Base http.RoundTripper
+	// Options of the retry of every request.
+	// The context of the request is set as the retry `Context`, LastErrorOnly is enabled by default.
+	Options []retry.Option
+	// Statuses of responses which are retried, `retry.DefaultRetryableHTTPStatuses` if nil
+	Statuses []int
+	// Policies of responses by status code, or by class of status codes with keys 1 to 5
+	// (e.g. 5 for every 5xx status without its own entry).
+	// When set, Statuses is ignored and responses with a status without a policy are not retried.
+	Policies map[int]Policy
+	// MaxBufferedBody is the size of request bodies without Request.GetBody up to which they are buffered in memory
+	// to be sent again by retries, `DefaultMaxBufferedBody` if 0; negative disables the buffering
+	MaxBufferedBody int64
+	// MaxDrainBody is the size of bodies of responses with a retryable status up to which they are read
+	// before the next attempt, so their connection is reused instead of closed, `DefaultMaxDrainBody` if 0;
+	// negative disables the draining
+	MaxDrainBody int64
+	// RetryNonIdempotent enables retries of requests with non-idempotent methods (e.g. POST or PATCH)
+	// without an Idempotency-Key header
+	RetryNonIdempotent bool
+	// Recorder records attempts with their timings if not nil, see `Timings`
+	Recorder *Recorder
+}
+
+const (
+	// DefaultMaxBufferedBody is the default of `Transport.MaxBufferedBody`
+	DefaultMaxBufferedBody = 64 << 10
+	// DefaultMaxDrainBody is the default of `Transport.MaxDrainBody`
+	DefaultMaxDrainBody = 64 << 10
+)
+
+// Policy is the retry behavior for responses with a status code, see `Transport.Policies`
+type Policy struct {
+	// Retry enables retries of responses with the status, they are returned as they are otherwise
+	Retry bool
+	// MaxRetries limits the count of retries after responses with the status,
+	// 0 means no limit other than the Attempts of the Options
+	MaxRetries uint
+	// Delay returns the delay after the n-th response with the status (starting from 1) if not nil,
+	// the DelayType of the Options is used otherwise
+	Delay func(n uint) time.Duration
+	// IgnoreRetryAfter disables honoring the Retry-After header of responses with the status
+	IgnoreRetryAfter bool
+}
+
+// RoundTrip implements http.RoundTripper.
+// Requests with a body are retried only when the body can be rewound by Request.GetBody
+// (which http.NewRequest sets for common body types) or when it is small enough to be buffered
+// (see `Transport.MaxBufferedBody`), other requests are sent once
+// as well as non-idempotent requests (see `Transport.RetryNonIdempotent`).
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !(t.RetryNonIdempotent || idempotent(req)) {
+		return t.attempt(req, 1)
+	}
+	if hasBody(req) && req.GetBody == nil {
+		buffered, ok, err := bufferBody(req, t.maxBufferedBody())
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return t.attempt(buffered, 1)
+		}
+		req = buffered
+	}
+
+	opts := make([]retry.Option, 0, len(t.Options)+2)
+	opts = append(opts, retry.LastErrorOnly(true))
+	opts = append(opts, t.Options...)
+	opts = append(opts, retry.Context(req.Context()))
+
+	var number uint
+	var statuses map[int]uint // count of responses by status, for Policy.MaxRetries
+	var last *http.Response   // the last response with a retryable status, returned if all attempts fail
+	resp, err := retry.DoWithData(func() (*http.Response, error) {
+		number++
+		if last != nil {
+			last.Body.Close()
+			last = nil
+		}
+
+		attemptReq, err := rewind(req, number)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.attempt(attemptReq, number)
+		if err != nil {
+			return nil, err
+		}
+		policy := t.policy(resp.StatusCode)
+		if !policy.Retry {
+			return resp, nil
+		}
+		if statuses == nil {
+			statuses = make(map[int]uint)
+		}
+		statuses[resp.StatusCode]++
+		n := statuses[resp.StatusCode]
+		if policy.MaxRetries > 0 && n > policy.MaxRetries {
+			return resp, nil
+		}
+		drain(resp, t.maxDrainBody())
+		last = resp
+		return nil, newStatusError(resp, policy, n)
+	}, opts...)
+	if err == nil {
+		return resp, nil
+	}
+
+	var statusErr *statusError
+	if last != nil && errors.As(err, &statusErr) && statusErr.resp == last {
+		return last, nil
+	}
+	if last != nil {
+		last.Body.Close()
+	}
+	return nil, err
+}
+
+// attempt makes a single round trip, recording it with its timings
+func (t *Transport) attempt(req *http.Request, number uint) (*http.Response, error) {
+	var tracer *tracer
+	if t.Recorder != nil {
+		tracer = newTracer()
+		req = req.WithContext(tracer.withClientTrace(req.Context()))
+	}
+
+	resp, err := t.base().RoundTrip(req)
+
+	if tracer != nil {
+		a := Attempt{
+			Number:   number,
+			Start:    tracer.start,
+			Duration: time.Since(tracer.start),
+			Err:      err,
+			Timings:  tracer.timings(),
+		}
+		if resp != nil {
+			a.StatusCode = resp.StatusCode
+		}
+		t.Recorder.record(a)
+	}
+
+	return resp, err
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base == nil {
+		return http.DefaultTransport
+	}
+	return t.Base
+}
+
+func (t *Transport) maxDrainBody() int64 {
+	if t.MaxDrainBody == 0 {
+		return DefaultMaxDrainBody
+	}
+	return t.MaxDrainBody
+}
+
+func (t *Transport) maxBufferedBody() int64 {
+	if t.MaxBufferedBody == 0 {
+		return DefaultMaxBufferedBody
+	}
+	return t.MaxBufferedBody
+}
+
+// policy returns the policy of responses with the status code
+func (t *Transport) policy(code int) Policy {
+	if t.Policies != nil {
+		if policy, ok := t.Policies[code]; ok {
+			return policy
+		}
+		return t.Policies[code/100]
+	}
+
+	statuses := t.Statuses
+	if statuses == nil {
+		statuses = retry.DefaultRetryableHTTPStatuses
+	}
+	for _, s := range statuses {
+		if code == s {
+			return Policy{Retry: true}
+		}
+	}
+	return Policy{}
+}
+
+// rewind returns the request for the given attempt, with a fresh body for retries
+func rewind(req *http.Request, number uint) (*http.Request, error) {
+	if number == 1 || !hasBody(req) {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, retry.Unrecoverable(err)
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, nil
+}
+
+// bufferBody reads the body of the request up to the limit.
+// It returns a copy of the request with the body buffered and GetBody set if the body fits,
+// or with the body replaying the read part followed by the rest of the original body otherwise.
+func bufferBody(req *http.Request, limit int64) (*http.Request, bool, error) {
+	if limit < 0 {
+		return req, false, nil
+	}
+
+	buf, body, ok, err := buffer(req.Body, limit)
+	if err != nil {
+		req.Body.Close()
+		return nil, false, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	if ok {
+		clone.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(buf)), nil
+		}
+	}
+	return clone, ok, nil
+}
+
+// drain reads the body of the response up to the limit and closes it,
+// so the connection returns to the pool for the next attempt while waiting for it.
+// The response keeps the content of the body in case it is returned after all.
+// Bodies over the limit stay open, their connection is closed with them.
+func drain(resp *http.Response, limit int64) {
+	if limit < 0 {
+		return
+	}
+	_, resp.Body, _, _ = buffer(resp.Body, limit)
+}
+
+// buffer reads rc up to the limit. When the content fits, rc is closed and the content is returned with true,
+// otherwise the returned body replays the read part followed by the rest of rc.
+func buffer(rc io.ReadCloser, limit int64) ([]byte, io.ReadCloser, bool, error) {
+	buf, err := io.ReadAll(io.LimitReader(rc, limit+1))
+	if err != nil || int64(len(buf)) > limit {
+		return buf, struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(buf), rc), rc}, false, err
+	}
+
+	rc.Close()
+	return buf, io.NopCloser(bytes.NewReader(buf)), true, nil
+}
+
+// idempotent checks if the request may be sent several times without duplicating its side effects
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != "" || req.Header.Get("X-Idempotency-Key") != ""
+}
+
+func hasBody(req *http.Request) bool {
+	return req.Body != nil && req.Body != http.NoBody
+}
+
+// statusError is the error of an attempt ended with a retryable status
+type statusError struct {
+	retry.HTTPError
+	resp       *http.Response
+	retryAfter time.Duration
+}
+
+// newStatusError returns the error of the n-th response with the status,
+// requesting the delay of the Retry-After header or the policy (negative for none, see `retry.RetryAfter`)
+func newStatusError(resp *http.Response, policy Policy, n uint) *statusError {
+	e := &statusError{
+		HTTPError:  retry.HTTPError{StatusCode: resp.StatusCode},
+		resp:       resp,
+		retryAfter: -1,
+	}
+	if !policy.IgnoreRetryAfter {
+		if d, ok := retry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			e.retryAfter = d
+		}
+	}
+	if e.retryAfter < 0 && policy.Delay != nil {
+		e.retryAfter = policy.Delay(n)
+	}
+	return e
+}
+
+// Unwrap returns the *retry.HTTPError, so it can be classified by `retry.RetryIfHTTPStatus`
+func (e *statusError) Unwrap() error {
+	return &e.HTTPError
+}
+
+// RetryAfter returns the delay requested by the Retry-After header, see `retry.RetryAfter`
+func (e *statusError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
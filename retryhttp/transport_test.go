@@ -0,0 +1,224 @@
+package retryhttp
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransport(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{Options: []retry.Option{retry.Delay(0)}}}
+	req, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("body"))
+	resp, err := client.Do(req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, []string{"body", "body", "body"}, bodies)
+}
+
+func TestTransportExhausted(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = io.WriteString(w, "slow down")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{Options: []retry.Option{retry.Attempts(2), retry.Delay(time.Hour)}}}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, "slow down", string(body))
+	assert.Equal(t, 2, calls)
+}
+
+func TestTransportNotRewindable(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	send := func(transport *Transport) {
+		bodies = nil
+		req, _ := http.NewRequest(http.MethodPut, server.URL, io.NopCloser(strings.NewReader("body")))
+		resp, err := transport.RoundTrip(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	options := []retry.Option{retry.Attempts(3), retry.Delay(0)}
+	send(&Transport{Options: options})
+	assert.Equal(t, []string{"body", "body", "body"}, bodies, "small body is buffered")
+
+	send(&Transport{Options: options, MaxBufferedBody: 2})
+	assert.Equal(t, []string{"body"}, bodies, "body over the limit is sent once")
+
+	send(&Transport{Options: options, MaxBufferedBody: -1})
+	assert.Equal(t, []string{"body"}, bodies, "buffering disabled")
+}
+
+func TestTransportIdempotency(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	post := func(transport *Transport, idempotencyKey string) int {
+		calls = 0
+		req, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("body"))
+		if idempotencyKey != "" {
+			req.Header.Set("Idempotency-Key", idempotencyKey)
+		}
+		resp, err := transport.RoundTrip(req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+		return calls
+	}
+
+	options := []retry.Option{retry.Attempts(3), retry.Delay(0)}
+	assert.Equal(t, 1, post(&Transport{Options: options}, ""), "POST is not retried by default")
+	assert.Equal(t, 3, post(&Transport{Options: options}, "8e03978e"), "POST with Idempotency-Key is retried")
+	assert.Equal(t, 3, post(&Transport{Options: options, RetryNonIdempotent: true}, ""), "opted in")
+}
+
+func TestTransportTimings(t *testing.T) {
+	calls := 0
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	recorder := &Recorder{}
+	client := &http.Client{Transport: &Transport{
+		Base:     server.Client().Transport,
+		Options:  []retry.Option{retry.Delay(0)},
+		Recorder: recorder,
+	}}
+	resp, err := client.Get(server.URL)
+	assert.NoError(t, err)
+	resp.Body.Close()
+
+	attempts := recorder.Attempts()
+	assert.Len(t, attempts, 2)
+
+	assert.Equal(t, uint(1), attempts[0].Number)
+	assert.Equal(t, http.StatusBadGateway, attempts[0].StatusCode)
+	assert.False(t, attempts[0].ReusedConn)
+	assert.Greater(t, attempts[0].Connect, time.Duration(0))
+	assert.Greater(t, attempts[0].TLSHandshake, time.Duration(0))
+	assert.Greater(t, attempts[0].TimeToFirstByte, time.Duration(0))
+
+	assert.Equal(t, uint(2), attempts[1].Number)
+	assert.Equal(t, http.StatusOK, attempts[1].StatusCode)
+	assert.True(t, attempts[1].ReusedConn, "the body of the retried response is closed, so the connection is reused")
+	assert.Zero(t, attempts[1].TLSHandshake)
+}
+
+func TestTransportPolicies(t *testing.T) {
+	var status int
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	var delays []time.Duration
+	client := &http.Client{Transport: &Transport{
+		Options: []retry.Option{
+			retry.Delay(time.Hour),
+			retry.OnDelay(func(n uint, d time.Duration) { delays = append(delays, d) }),
+		},
+		Policies: map[int]Policy{
+			http.StatusServiceUnavailable: {Retry: true, MaxRetries: 2, Delay: func(n uint) time.Duration {
+				return time.Duration(n) * time.Millisecond
+			}},
+			http.StatusNotImplemented: {},
+			5:                         {Retry: true, Delay: func(uint) time.Duration { return 0 }},
+		},
+	}}
+
+	for _, c := range []struct {
+		status int
+		calls  int
+		delays []time.Duration
+	}{
+		{http.StatusServiceUnavailable, 3, []time.Duration{time.Millisecond, 2 * time.Millisecond}},
+		{http.StatusNotImplemented, 1, nil},
+		{http.StatusInternalServerError, 10, make([]time.Duration, 9)},
+		{http.StatusTooManyRequests, 1, nil},
+	} {
+		status, calls, delays = c.status, 0, nil
+		resp, err := client.Get(server.URL)
+		assert.NoError(t, err)
+		resp.Body.Close()
+
+		assert.Equal(t, c.status, resp.StatusCode)
+		assert.Equal(t, c.calls, calls, "status %d", c.status)
+		assert.Equal(t, c.delays, delays, "status %d", c.status)
+	}
+}
+
+func TestTransportDrain(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, strings.Repeat("x", 1024))
+	}))
+	defer server.Close()
+
+	get := func(transport *Transport) ([]Attempt, string) {
+		calls = 0
+		transport.Base = &http.Transport{}
+		transport.Options = []retry.Option{retry.Attempts(2), retry.Delay(0)}
+		transport.Recorder = &Recorder{}
+		req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		resp, err := transport.RoundTrip(req)
+		assert.NoError(t, err)
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return transport.Recorder.Attempts(), string(body)
+	}
+
+	attempts, body := get(&Transport{})
+	assert.Len(t, attempts, 2)
+	assert.True(t, attempts[1].ReusedConn, "drained connection is reused")
+	assert.Len(t, body, 1024, "the returned response keeps its body")
+
+	attempts, body = get(&Transport{MaxDrainBody: 16})
+	assert.Len(t, attempts, 2)
+	assert.Len(t, body, 1024, "body over the limit is kept as well")
+}
@@ -0,0 +1,127 @@
+/*
+Package retryio provides io.Reader and io.Writer wrappers retrying failed reads and writes
+with github.com/avast/retry-go
+
+resume a large download at the offset where the connection dropped:
+
+	r := retryio.NewReader(func(offset int64) (io.ReadCloser, error) {
+		req, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			return nil, retry.Unrecoverable(err)
+		}
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		return resp.Body, nil
+	}, retry.Attempts(5))
+	defer r.Close()
+
+	_, err := io.Copy(f, r)
+*/
+package retryio
+
+import (
+	"errors"
+	"io"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// ErrClosed is returned by Read and Write after Close
+var ErrClosed = errors.New("retryio: closed")
+
+// Reader reads a stream opened by a factory at an offset. When a read fails, the stream is closed
+// and reopened at the offset of the first unread byte (e.g. with an HTTP Range request or by seeking a file),
+// with attempts and delays of the retry policy, so large downloads survive transient drops.
+// Data read before an error is returned first, the stream is reopened by the next Read.
+//
+// Every Read is retried separately, so a stream making progress between drops is read to the end.
+// Errors of the factory are retried like read errors, wrap them by `retry.Unrecoverable` to stop.
+// Once the retry fails, its error is returned by all further reads.
+//
+// Reader is not safe for concurrent use.
+type Reader struct {
+	open func(offset int64) (io.ReadCloser, error)
+	opts []retry.Option
+
+	rc     io.ReadCloser
+	offset int64
+	err    error
+}
+
+// NewReader returns a Reader opening the stream by open, retrying with the options.
+// The errors returned by Read are the errors of the last attempt (`retry.LastErrorOnly`) unless opts say otherwise.
+func NewReader(open func(offset int64) (io.ReadCloser, error), opts ...retry.Option) *Reader {
+	return &Reader{
+		open: open,
+		opts: append([]retry.Option{retry.LastErrorOnly(true)}, opts...),
+	}
+}
+
+// Offset returns count of bytes read so far, the offset of the next Read
+func (r *Reader) Offset() int64 {
+	return r.offset
+}
+
+// Read reads from the stream, reopening it at the current offset on errors.
+// io.EOF of the stream is returned as is, without retrying.
+func (r *Reader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	var eof bool
+	n, err := retry.DoWithData(func() (int, error) {
+		if r.rc == nil {
+			rc, err := r.open(r.offset)
+			if err != nil {
+				return 0, err
+			}
+			r.rc = rc
+		}
+
+		n, err := r.rc.Read(p)
+		r.offset += int64(n)
+		switch {
+		case err == io.EOF:
+			eof = true
+			return n, nil
+		case err != nil:
+			r.rc.Close()
+			r.rc = nil
+			if n > 0 {
+				return n, nil
+			}
+			return 0, err
+		}
+		return n, nil
+	}, r.opts...)
+
+	if err != nil {
+		r.err = err
+		return 0, err
+	}
+	if eof {
+		r.err = io.EOF
+		if n == 0 {
+			return 0, io.EOF
+		}
+	}
+	return n, nil
+}
+
+// Close closes the current stream, if any
+func (r *Reader) Close() error {
+	r.err = ErrClosed
+	if r.rc == nil {
+		return nil
+	}
+	err := r.rc.Close()
+	r.rc = nil
+	return err
+}
@@ -0,0 +1,77 @@
+package retryio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyReader fails with err after limit bytes
+type flakyReader struct {
+	r      io.Reader
+	limit  int
+	err    error
+	closed bool
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	if f.limit <= 0 {
+		return 0, f.err
+	}
+	if len(p) > f.limit {
+		p = p[:f.limit]
+	}
+	n, err := f.r.Read(p)
+	f.limit -= n
+	return n, err
+}
+
+func (f *flakyReader) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestReader(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+
+	var offsets []int64
+	var streams []*flakyReader
+	r := NewReader(func(offset int64) (io.ReadCloser, error) {
+		offsets = append(offsets, offset)
+		if len(offsets) == 2 {
+			return nil, errors.New("connection refused")
+		}
+		stream := &flakyReader{r: bytes.NewReader(data[offset:]), limit: 300, err: io.ErrUnexpectedEOF}
+		streams = append(streams, stream)
+		return stream, nil
+	}, retry.Delay(0), retry.MaxJitter(0))
+
+	read, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, data, read)
+	assert.Equal(t, int64(len(data)), r.Offset())
+	assert.Equal(t, []int64{0, 300, 300, 600, 900}, offsets)
+
+	assert.NoError(t, r.Close())
+	for _, stream := range streams {
+		assert.True(t, stream.closed)
+	}
+	_, err = r.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestReaderGivingUp(t *testing.T) {
+	testErr := errors.New("test")
+	r := NewReader(func(offset int64) (io.ReadCloser, error) {
+		return &flakyReader{r: bytes.NewReader(nil), err: testErr}, nil
+	}, retry.Attempts(2), retry.Delay(0), retry.MaxJitter(0))
+
+	_, err := r.Read(make([]byte, 10))
+	assert.Equal(t, testErr, err)
+	_, err = r.Read(make([]byte, 10))
+	assert.Equal(t, testErr, err)
+}
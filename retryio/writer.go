@@ -0,0 +1,63 @@
+package retryio
+
+import (
+	"io"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Writer retries failed writes to a flaky pipe, socket or object storage stream
+// before surfacing the error. The unacknowledged rest of the chunk of a failed or short write is written again,
+// with attempts and delays of the retry policy, so no byte is written twice nor skipped.
+// Once the retry fails, its error is returned by all further writes.
+//
+// Writer is not safe for concurrent use.
+//
+//	w := retryio.NewWriter(conn, retry.Attempts(3), retry.Delay(50*time.Millisecond))
+//	_, err := io.Copy(w, src)
+type Writer struct {
+	w    io.Writer
+	opts []retry.Option
+	err  error
+}
+
+// NewWriter returns a Writer retrying writes to w with the options.
+// Only transient stream I/O errors are retried (see `retry.IsTransientIOError`) unless a RetryIf in opts says otherwise,
+// the errors returned by Write are the errors of the last attempt (`retry.LastErrorOnly`).
+func NewWriter(w io.Writer, opts ...retry.Option) *Writer {
+	return &Writer{
+		w:    w,
+		opts: append([]retry.Option{retry.RetryIf(retry.IsTransientIOError), retry.LastErrorOnly(true)}, opts...),
+	}
+}
+
+// Write writes the whole p, retrying the unwritten rest of it on errors,
+// returns count of bytes written including the ones of failed attempts
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+
+	var written int
+	err := retry.Do(func() error {
+		n, err := w.w.Write(p[written:])
+		written += n
+		if err == nil && written < len(p) {
+			err = io.ErrShortWrite
+		}
+		return err
+	}, w.opts...)
+	if err != nil {
+		w.err = err
+	}
+	return written, err
+}
+
+// Close closes the underlying writer if it is an io.Closer
+func (w *Writer) Close() error {
+	w.err = ErrClosed
+	if closer, ok := w.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package retryio
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyWriter accepts at most limit bytes per write and fails every other write
+type flakyWriter struct {
+	bytes.Buffer
+	limit  int
+	err    error
+	writes int
+}
+
+func (f *flakyWriter) Write(p []byte) (int, error) {
+	f.writes++
+	if f.writes%2 == 0 {
+		return 0, f.err
+	}
+	if len(p) > f.limit {
+		p = p[:f.limit]
+	}
+	return f.Buffer.Write(p)
+}
+
+func TestWriter(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10)
+	flaky := &flakyWriter{limit: 30, err: io.ErrUnexpectedEOF}
+	w := NewWriter(flaky, retry.Delay(0), retry.MaxJitter(0))
+
+	n, err := w.Write(data)
+	assert.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	assert.Equal(t, data, flaky.Bytes())
+
+	assert.NoError(t, w.Close())
+	_, err = w.Write(data)
+	assert.ErrorIs(t, err, ErrClosed)
+}
+
+func TestWriterGivingUp(t *testing.T) {
+	testErr := errors.New("permission denied")
+	flaky := &flakyWriter{limit: 30, err: testErr}
+	w := NewWriter(flaky, retry.Delay(0), retry.MaxJitter(0))
+
+	n, err := w.Write(make([]byte, 100))
+	assert.Equal(t, testErr, err, "not transient")
+	assert.Equal(t, 30, n)
+	assert.Equal(t, 2, flaky.writes)
+
+	_, err = w.Write(make([]byte, 100))
+	assert.Equal(t, testErr, err)
+	assert.Equal(t, 2, flaky.writes)
+}
@@ -0,0 +1,74 @@
+/*
+Package retrykafka provides Kafka consumer helpers for github.com/avast/retry-go
+
+It does not depend on any Kafka client, messages are generic, so it works with
+*sarama.ConsumerMessage of IBM/sarama as well as kafka.Message of segmentio/kafka-go.
+
+retry processing of every message of a sarama consumer group claim:
+
+	process := retrykafka.WithRetry(
+		func(ctx context.Context, msg *sarama.ConsumerMessage) error {
+			return store(ctx, msg.Value)
+		},
+		retry.Attempts(5),
+		retry.Delay(time.Second),
+	)
+
+	for msg := range claim.Messages() {
+		if err := process(session.Context(), msg); err != nil {
+			// e.g. send the message to a dead-letter topic
+		}
+		session.MarkMessage(msg, "")
+	}
+*/
+package retrykafka
+
+import (
+	"context"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Handler processes a single consumed message.
+// The context is cancelled when the consumer stops (e.g. on rebalance), which stops the retries too.
+type Handler[M any] func(ctx context.Context, msg M) error
+
+// Pauser pauses fetching of messages, e.g. of a partition.
+// sarama.PartitionConsumer satisfies Pauser.
+type Pauser interface {
+	Pause()
+	Resume()
+}
+
+// WithRetry wraps the handler, so every message is retried with the given options.
+// The returned handler returns the error of `retry.DoContext` when all attempts fail.
+func WithRetry[M any](handler Handler[M], opts ...retry.Option) Handler[M] {
+	return WithRetryPausing(handler, nil, opts...)
+}
+
+// WithRetryPausing is `WithRetry` which pauses the pauser while backing off between attempts
+// and resumes it before the next attempt, so the client does not buffer messages
+// (and does not hit session timeouts) while a message is being retried.
+// The pauser is always resumed before the handler returns. A nil pauser is not paused.
+func WithRetryPausing[M any](handler Handler[M], pauser Pauser, opts ...retry.Option) Handler[M] {
+	return func(ctx context.Context, msg M) error {
+		paused := false
+		resume := func() {
+			if paused {
+				pauser.Resume()
+				paused = false
+			}
+		}
+		defer resume()
+
+		return retry.DoContext(ctx, func(ctx context.Context) error {
+			resume()
+			err := handler(ctx, msg)
+			if err != nil && pauser != nil {
+				pauser.Pause()
+				paused = true
+			}
+			return err
+		}, opts...)
+	}
+}
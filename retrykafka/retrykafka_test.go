@@ -0,0 +1,72 @@
+package retrykafka
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type message struct {
+	offset int64
+}
+
+type pauser struct {
+	paused  bool
+	pauses  int
+	resumes int
+}
+
+func (p *pauser) Pause() {
+	p.paused = true
+	p.pauses++
+}
+
+func (p *pauser) Resume() {
+	p.paused = false
+	p.resumes++
+}
+
+func TestWithRetry(t *testing.T) {
+	var offsets []int64
+	process := WithRetry(func(ctx context.Context, msg message) error {
+		offsets = append(offsets, msg.offset)
+		if len(offsets) < 3 {
+			return errors.New("test")
+		}
+		return nil
+	}, retry.Delay(0))
+
+	assert.NoError(t, process(context.Background(), message{offset: 42}))
+	assert.Equal(t, []int64{42, 42, 42}, offsets)
+}
+
+func TestWithRetryPausing(t *testing.T) {
+	p := &pauser{}
+	process := WithRetryPausing(func(ctx context.Context, msg message) error {
+		assert.False(t, p.paused, "attempts run with resumed pauser")
+		return errors.New("test")
+	}, p, retry.Attempts(3), retry.Delay(0))
+
+	err := process(context.Background(), message{})
+	assert.Error(t, err)
+	assert.False(t, p.paused)
+	assert.Equal(t, 3, p.pauses)
+	assert.Equal(t, 3, p.resumes)
+}
+
+func TestWithRetryCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	process := WithRetry(func(ctx context.Context, msg message) error {
+		calls++
+		cancel()
+		return errors.New("test")
+	}, retry.Delay(0))
+
+	err := process(ctx, message{})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,18 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly)
+
+package retrylock
+
+import (
+	"errors"
+	"os"
+	"runtime"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// errUnsupported is errors.ErrUnsupported, which is available since go1.21
+var errUnsupported = errors.New("unsupported operation")
+
+func flock(f *os.File) error {
+	return retry.Unrecoverable(&os.PathError{Op: "flock on " + runtime.GOOS, Path: f.Name(), Err: errUnsupported})
+}
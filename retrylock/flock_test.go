@@ -0,0 +1,34 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package retrylock
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	ctx := context.Background()
+	opts := []retry.Option{retry.Attempts(3), retry.Delay(time.Millisecond), retry.MaxJitter(time.Millisecond)}
+
+	lock, err := Flock(ctx, path, opts...)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = Flock(ctx, path, opts...)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	assert.NoError(t, lock.Unlock())
+	lock, err = Flock(ctx, path, opts...)
+	assert.NoError(t, err)
+	assert.NoError(t, lock.Unlock())
+	_, err = os.Stat(path)
+	assert.NoError(t, err, "the file is kept")
+}
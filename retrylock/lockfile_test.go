@@ -0,0 +1,50 @@
+package retrylock
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.lock")
+	ctx := context.Background()
+	opts := []retry.Option{retry.Attempts(3), retry.Delay(time.Millisecond), retry.MaxJitter(time.Millisecond)}
+
+	lock, err := LockFile(ctx, path, opts...)
+	if !assert.NoError(t, err) {
+		return
+	}
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(os.Getpid()), strings.TrimSpace(string(content)))
+
+	_, err = LockFile(ctx, path, opts...)
+	assert.ErrorIs(t, err, ErrLocked)
+
+	// released while waiting
+	held := lock
+	time.AfterFunc(20*time.Millisecond, func() { _ = held.Unlock() })
+	lock, err = LockFile(ctx, path, retry.Attempts(0), retry.Delay(time.Millisecond))
+	assert.NoError(t, err)
+	assert.NoError(t, lock.Unlock())
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLockUnrecoverable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "test.lock")
+
+	var attempts uint
+	_, err := LockFile(context.Background(), path, retry.OnRetry(func(n uint, err error) { attempts++ }))
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+	assert.Equal(t, uint(0), attempts)
+}
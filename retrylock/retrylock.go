@@ -0,0 +1,116 @@
+/*
+Package retrylock acquires file locks with github.com/avast/retry-go,
+for CLI tools and jobs coordinating on shared state
+
+wait up to about a minute for another process holding the lock:
+
+	lock, err := retrylock.LockFile(ctx, "/var/lock/myapp.lock",
+		retry.Attempts(10),
+		retry.Delay(time.Second),
+	)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
+Attempts are made with backoff and jitter (the default delay type of the retry).
+Permission problems and missing directories can't be fixed by waiting, they stop the retry immediately
+with an unrecoverable error.
+*/
+package retrylock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// ErrLocked is the error of an attempt to acquire a lock held by another process (or another Lock)
+var ErrLocked = errors.New("retrylock: locked")
+
+// Lock is an acquired lock, released by Unlock
+type Lock struct {
+	file   *os.File
+	path   string
+	remove bool // the lock is the existence of the file
+}
+
+// Path returns the path of the lock file
+func (l *Lock) Path() string {
+	return l.path
+}
+
+// Unlock releases the lock
+func (l *Lock) Unlock() error {
+	if l.remove {
+		// the file is closed first, open files can't be removed on Windows
+		err := l.file.Close()
+		if removeErr := os.Remove(l.path); err == nil {
+			err = removeErr
+		}
+		return err
+	}
+	// closing the file releases the advisory lock
+	return l.file.Close()
+}
+
+// LockFile acquires a lock file: the file is created exclusively, attempts fail with ErrLocked while it exists.
+// The PID of the process is written into the file. Unlock removes the file.
+// Lock files work on every platform and file system, but they stay behind when the process crashes;
+// see `Flock` for locks released by the operating system.
+func LockFile(ctx context.Context, path string, opts ...retry.Option) (*Lock, error) {
+	return acquire(ctx, func() (*Lock, error) {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o644)
+		if err != nil {
+			if errors.Is(err, fs.ErrExist) {
+				return nil, fmt.Errorf("%w: %s exists", ErrLocked, path)
+			}
+			return nil, classify(err)
+		}
+		if _, err := fmt.Fprintf(f, "%d\n", os.Getpid()); err != nil {
+			f.Close()
+			os.Remove(path)
+			return nil, err
+		}
+		return &Lock{file: f, path: path, remove: true}, nil
+	}, opts)
+}
+
+// Flock acquires an exclusive advisory lock (flock) of the file, creating it if needed;
+// attempts fail with ErrLocked while another process holds the lock.
+// The lock is released by Unlock or by the operating system when the process exits, the file is kept.
+// Flock is supported on Linux, macOS and BSDs, it fails with an unrecoverable error elsewhere.
+func Flock(ctx context.Context, path string, opts ...retry.Option) (*Lock, error) {
+	return acquire(ctx, func() (*Lock, error) {
+		f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+		if err != nil {
+			return nil, classify(err)
+		}
+		if err := flock(f); err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &Lock{file: f, path: path}, nil
+	}, opts)
+}
+
+// acquire retries the attempt to acquire a lock with the options
+func acquire(ctx context.Context, attempt func() (*Lock, error), opts []retry.Option) (*Lock, error) {
+	lockOpts := make([]retry.Option, 0, len(opts)+2)
+	lockOpts = append(lockOpts, retry.LastErrorOnly(true))
+	lockOpts = append(lockOpts, opts...)
+	lockOpts = append(lockOpts, retry.Context(ctx))
+	return retry.DoWithData(attempt, lockOpts...)
+}
+
+// classify marks errors which waiting can't fix as unrecoverable
+func classify(err error) error {
+	if errors.Is(err, fs.ErrPermission) || errors.Is(err, fs.ErrNotExist) {
+		return retry.Unrecoverable(err)
+	}
+	return err
+}
@@ -0,0 +1,105 @@
+/*
+Package retryredis provides Redis error classifiers for github.com/avast/retry-go
+
+It does not depend on any Redis client, errors are recognized by their shape,
+so it works with redis/go-redis as well as gomodule/redigo.
+
+retry a Redis call only on transient errors:
+
+	err := retry.Do(
+		func() error {
+			return rdb.Set(ctx, key, value, 0).Err()
+		},
+		retry.RetryIf(retryredis.IsTransient),
+	)
+*/
+package retryredis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// TransientReplyPrefixes are the prefixes of Redis error replies which are recognized as transient by `IsTransient`:
+// the server is loading the dataset, the replica is read-only after a failover,
+// the cluster is down or the keys are being migrated.
+var TransientReplyPrefixes = []string{
+	"LOADING ",
+	"READONLY ",
+	"CLUSTERDOWN ",
+	"TRYAGAIN ",
+	"MASTERDOWN ",
+}
+
+// poolExhaustedMessages are the messages of the pool exhaustion errors
+// (`redis.ErrPoolTimeout` of go-redis, `redis.ErrPoolExhausted` of redigo)
+var poolExhaustedMessages = []string{
+	"redis: connection pool timeout",
+	"redigo: connection pool exhausted",
+}
+
+// IsTransient checks if the error (or any error it wraps) is a transient Redis error:
+// one of `TransientReplyPrefixes` error replies, connection pool exhaustion,
+// a network timeout, a closed connection or a transient OS error (see `retry.IsTransientOSError`).
+//
+// Context errors, unrecoverable errors and "nil" replies (`redis.Nil`) are not transient.
+func IsTransient(err error) bool {
+	if err == nil || retry.IsUnrecoverable(err) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if IsTransientReply(err) || IsPoolExhausted(err) || retry.IsTransientOSError(err) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsTransientReply checks if the error (or any error it wraps) is a Redis error reply
+// starting with one of `TransientReplyPrefixes`
+func IsTransientReply(err error) bool {
+	return anyMessage(err, func(msg string) bool {
+		for _, prefix := range TransientReplyPrefixes {
+			if strings.HasPrefix(msg, prefix) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// IsPoolExhausted checks if the error (or any error it wraps) reports that no connection
+// could be taken from the connection pool in time
+func IsPoolExhausted(err error) bool {
+	return anyMessage(err, func(msg string) bool {
+		for _, m := range poolExhaustedMessages {
+			if msg == m {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// anyMessage checks the messages of the error and all errors it wraps,
+// so errors annotated with fmt.Errorf("...: %w", err) are recognized too
+func anyMessage(err error, match func(string) bool) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if match(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+//go:build !plan9
+
+package retryredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// redisError mimics error replies of go-redis (proto.RedisError) and redigo (redis.Error)
+type redisError string
+
+func (e redisError) Error() string { return string(e) }
+
+func TestIsTransient(t *testing.T) {
+	for _, err := range []error{
+		redisError("LOADING Redis is loading the dataset in memory"),
+		redisError("READONLY You can't write against a read only replica."),
+		redisError("CLUSTERDOWN The cluster is down"),
+		redisError("TRYAGAIN Multiple keys request during rehashing of slot"),
+		fmt.Errorf("set key: %w", redisError("MASTERDOWN Link with MASTER is down")),
+		errors.New("redis: connection pool timeout"),
+		errors.New("redigo: connection pool exhausted"),
+		io.EOF,
+		&net.OpError{Op: "read", Err: syscall.ECONNRESET},
+		&net.DNSError{IsTimeout: true},
+	} {
+		assert.True(t, IsTransient(err), err.Error())
+	}
+
+	for _, err := range []error{
+		nil,
+		errors.New("redis: nil"),
+		redisError("WRONGTYPE Operation against a key holding the wrong kind of value"),
+		redisError("ERR unknown command"),
+		retry.Unrecoverable(redisError("LOADING Redis is loading the dataset in memory")),
+		context.Canceled,
+		context.DeadlineExceeded,
+	} {
+		assert.False(t, IsTransient(err), fmt.Sprint(err))
+	}
+}
+
+func TestRetryIfIsTransient(t *testing.T) {
+	calls := 0
+	err := retry.Do(func() error {
+		calls++
+		if calls < 3 {
+			return redisError("LOADING Redis is loading the dataset in memory")
+		}
+		return redisError("WRONGTYPE Operation against a key holding the wrong kind of value")
+	}, retry.RetryIf(IsTransient), retry.Delay(0))
+
+	assert.Error(t, err)
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,69 @@
+package retrysql
+
+import (
+	"errors"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PgTransientSQLStates are the SQLSTATE codes of `*pgconn.PgError` recognized by `IsPgTransient`:
+// serialization_failure, deadlock_detected and admin_shutdown (the server is restarting)
+var PgTransientSQLStates = []string{
+	"40001",
+	"40P01",
+	"57P01",
+}
+
+// MySQLTransientErrors are the error numbers of `*mysql.MySQLError` recognized by `IsMySQLTransient`:
+// ER_LOCK_DEADLOCK, ER_LOCK_WAIT_TIMEOUT and CR_SERVER_GONE_ERROR
+var MySQLTransientErrors = []uint16{
+	1213,
+	1205,
+	2006,
+}
+
+// IsPgTransient checks if the error (or any error it wraps) is a `*pgconn.PgError` of jackc/pgx
+// with one of `PgTransientSQLStates`. Unrecoverable errors are not transient.
+func IsPgTransient(err error) bool {
+	var pgErr *pgconn.PgError
+	if retry.IsUnrecoverable(err) || !errors.As(err, &pgErr) {
+		return false
+	}
+
+	for _, state := range PgTransientSQLStates {
+		if pgErr.Code == state {
+			return true
+		}
+	}
+	return false
+}
+
+// IsMySQLTransient checks if the error (or any error it wraps) is a `*mysql.MySQLError` of go-sql-driver/mysql
+// with one of `MySQLTransientErrors`, or `mysql.ErrInvalidConn` (the connection was lost).
+// Unrecoverable errors are not transient.
+func IsMySQLTransient(err error) bool {
+	if retry.IsUnrecoverable(err) {
+		return false
+	}
+	if errors.Is(err, mysql.ErrInvalidConn) {
+		return true
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) {
+		return false
+	}
+	for _, number := range MySQLTransientErrors {
+		if mysqlErr.Number == number {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTransient checks if the error is transient by `IsPgTransient` or `IsMySQLTransient`
+func IsTransient(err error) bool {
+	return IsPgTransient(err) || IsMySQLTransient(err)
+}
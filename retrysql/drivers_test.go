@@ -0,0 +1,45 @@
+package retrysql
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPgTransient(t *testing.T) {
+	for _, code := range []string{"40001", "40P01", "57P01"} {
+		assert.True(t, IsPgTransient(&pgconn.PgError{Code: code}), code)
+		assert.True(t, IsTransient(fmt.Errorf("wrap: %w", &pgconn.PgError{Code: code})), code)
+	}
+	assert.False(t, IsPgTransient(&pgconn.PgError{Code: "23505"}))
+	assert.False(t, IsPgTransient(retry.Unrecoverable(&pgconn.PgError{Code: "40001"})))
+	assert.False(t, IsPgTransient(errors.New("ERROR: could not serialize access (SQLSTATE 40001)")))
+}
+
+func TestIsMySQLTransient(t *testing.T) {
+	for _, number := range []uint16{1213, 1205, 2006} {
+		assert.True(t, IsMySQLTransient(&mysql.MySQLError{Number: number}), number)
+		assert.True(t, IsTransient(fmt.Errorf("wrap: %w", &mysql.MySQLError{Number: number})), number)
+	}
+	assert.True(t, IsMySQLTransient(mysql.ErrInvalidConn))
+	assert.False(t, IsMySQLTransient(&mysql.MySQLError{Number: 1062}))
+	assert.False(t, IsMySQLTransient(retry.Unrecoverable(&mysql.MySQLError{Number: 1213})))
+	assert.False(t, IsMySQLTransient(errors.New("Error 1213: Deadlock found when trying to get lock")))
+}
+
+func TestSQLStateOfDrivers(t *testing.T) {
+	state, ok := SQLState(&pgconn.PgError{Code: "40P01"})
+	assert.True(t, ok)
+	assert.Equal(t, "40P01", state)
+
+	deadlock := &mysql.MySQLError{Number: 1213, SQLState: [5]byte{'4', '0', '0', '0', '1'}}
+	state, ok = SQLState(deadlock)
+	assert.True(t, ok)
+	assert.Equal(t, "40001", state)
+	assert.True(t, IsRetryableTx(deadlock))
+}
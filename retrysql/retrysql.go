@@ -0,0 +1,115 @@
+/*
+Package retrysql provides database/sql helpers for github.com/avast/retry-go
+
+It is a separate module, so the core package does not depend on database drivers.
+
+retry a transaction aborted by a serialization failure or a deadlock:
+
+	err := retrysql.Tx(ctx, db, func(tx *sql.Tx) error {
+		var balance int
+		if err := tx.QueryRowContext(ctx, "SELECT balance FROM accounts WHERE id = $1", id).Scan(&balance); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = $1 WHERE id = $2", balance+amount, id)
+		return err
+	}, retry.Attempts(5))
+
+retry a query on transient errors of jackc/pgx or go-sql-driver/mysql:
+
+	err := retry.Do(
+		func() error {
+			_, err := db.ExecContext(ctx, query)
+			return err
+		},
+		retry.RetryIf(retrysql.IsTransient),
+	)
+*/
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/go-sql-driver/mysql"
+)
+
+// RetryableSQLStates are the SQLSTATE codes of errors aborting a transaction which may succeed when run again:
+// serialization_failure (also used by CockroachDB for "restart transaction") and deadlock_detected
+var RetryableSQLStates = []string{
+	"40001",
+	"40P01",
+}
+
+// Beginner starts transactions, it is implemented by *sql.DB and *sql.Conn
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// SQLState returns the SQLSTATE code of the error (or any error it wraps).
+// It recognizes `*mysql.MySQLError` of go-sql-driver/mysql and errors of drivers implementing `SQLState() string`,
+// e.g. jackc/pgx and lib/pq.
+func SQLState(err error) (string, bool) {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return string(mysqlErr.SQLState[:]), true
+	}
+
+	var sqlStateErr interface{ SQLState() string }
+	if !errors.As(err, &sqlStateErr) {
+		return "", false
+	}
+	return sqlStateErr.SQLState(), true
+}
+
+// IsRetryableTx checks if the error aborted a transaction which may succeed when run again,
+// i.e. its SQLSTATE is one of `RetryableSQLStates`. Unrecoverable errors are not retryable.
+func IsRetryableTx(err error) bool {
+	if retry.IsUnrecoverable(err) {
+		return false
+	}
+
+	state, ok := SQLState(err)
+	if !ok {
+		return false
+	}
+	for _, retryable := range RetryableSQLStates {
+		if state == retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// Tx runs fn in a transaction and commits it. When fn or the commit fails, the transaction is rolled back
+// and the whole transaction is retried with a new one if the error is retryable by `IsRetryableTx`.
+// Options can override the classification by RetryIf; LastErrorOnly is enabled by default
+// and ctx is set as the retry `Context`.
+//
+// fn may be called several times, so it must not have side effects outside of the transaction.
+func Tx(ctx context.Context, db Beginner, fn func(tx *sql.Tx) error, opts ...retry.Option) error {
+	return TxWithOptions(ctx, db, nil, fn, opts...)
+}
+
+// TxWithOptions is `Tx` beginning the transactions with txOpts (e.g. an isolation level or read-only),
+// nil for the defaults of the driver.
+func TxWithOptions(ctx context.Context, db Beginner, txOpts *sql.TxOptions, fn func(tx *sql.Tx) error, opts ...retry.Option) error {
+	options := make([]retry.Option, 0, len(opts)+3)
+	options = append(options, retry.RetryIf(IsRetryableTx), retry.LastErrorOnly(true))
+	options = append(options, opts...)
+	options = append(options, retry.Context(ctx))
+
+	return retry.Do(func() error {
+		tx, err := db.BeginTx(ctx, txOpts)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(tx); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		return tx.Commit()
+	}, options...)
+}
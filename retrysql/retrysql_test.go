@@ -0,0 +1,119 @@
+package retrysql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "SQLSTATE " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
+
+// fakeDriver counts transactions and fails commits with the queued errors
+type fakeDriver struct {
+	mu         sync.Mutex
+	commitErrs []error
+	commits    int
+	rollbacks  int
+}
+
+func (d *fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ d *fakeDriver }
+
+func (c fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c fakeConn) Close() error                        { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)           { return fakeTx(c), nil }
+
+type fakeTx struct{ d *fakeDriver }
+
+func (tx fakeTx) Commit() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.commits++
+	if len(tx.d.commitErrs) > 0 {
+		err := tx.d.commitErrs[0]
+		tx.d.commitErrs = tx.d.commitErrs[1:]
+		return err
+	}
+	return nil
+}
+
+func (tx fakeTx) Rollback() error {
+	tx.d.mu.Lock()
+	defer tx.d.mu.Unlock()
+	tx.d.rollbacks++
+	return nil
+}
+
+func openFake(t *testing.T, commitErrs ...error) (*sql.DB, *fakeDriver) {
+	d := &fakeDriver{commitErrs: commitErrs}
+	name := fmt.Sprintf("retrysql-fake-%s", t.Name())
+	sql.Register(name, d)
+	db, err := sql.Open(name, "")
+	assert.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestIsRetryableTx(t *testing.T) {
+	assert.True(t, IsRetryableTx(sqlStateError("40001")))
+	assert.True(t, IsRetryableTx(fmt.Errorf("wrap: %w", sqlStateError("40P01"))))
+	assert.False(t, IsRetryableTx(sqlStateError("23505")))
+	assert.False(t, IsRetryableTx(retry.Unrecoverable(sqlStateError("40001"))))
+	assert.False(t, IsRetryableTx(errors.New("40001")))
+}
+
+func TestTx(t *testing.T) {
+	db, d := openFake(t, sqlStateError("40001"))
+
+	calls := 0
+	err := Tx(context.Background(), db, func(tx *sql.Tx) error {
+		calls++
+		if calls == 1 {
+			return sqlStateError("40P01")
+		}
+		return nil
+	}, retry.Delay(time.Nanosecond))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls, "deadlock in fn and serialization failure on commit are retried")
+	assert.Equal(t, 2, d.commits)
+	assert.Equal(t, 1, d.rollbacks)
+}
+
+func TestTxNotRetryable(t *testing.T) {
+	db, d := openFake(t)
+
+	calls := 0
+	uniqueViolation := sqlStateError("23505")
+	err := Tx(context.Background(), db, func(tx *sql.Tx) error {
+		calls++
+		return uniqueViolation
+	})
+	assert.Equal(t, uniqueViolation, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, d.rollbacks)
+}
+
+func TestTxWithOptions(t *testing.T) {
+	db, _ := openFake(t)
+
+	calls := 0
+	err := TxWithOptions(context.Background(), db, &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		calls++
+		return nil
+	})
+	// the fake driver supports only the default transactions, so the options reached BeginTx
+	assert.ErrorContains(t, err, "read-only")
+	assert.Equal(t, 0, calls)
+}
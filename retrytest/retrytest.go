@@ -0,0 +1,165 @@
+/*
+Package retrytest provides helpers for testing code using github.com/avast/retry-go
+without real sleeps or bespoke mocks.
+
+	timer := retrytest.NewTimer()
+	recorder := &retrytest.Recorder{}
+
+	err := retry.Do(
+		recorder.Wrap(func() error { ... }),
+		retry.Attempts(3),
+		retry.WithTimer(timer),
+	)
+
+	retrytest.AssertAttempts(t, err, 3)
+	// recorder.Attempts() == 3, timer.Delays() has 2 items
+
+or in one go with Run:
+
+	outcome := retrytest.Run(t, func() error { ... }, retry.Attempts(3))
+	// outcome.Attempts == 3, outcome.Delays has 2 items
+*/
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Timer is a retry.Timer which fires immediately and records requested durations
+type Timer struct {
+	mu     sync.Mutex
+	delays []time.Duration
+}
+
+// NewTimer creates an instant Timer
+func NewTimer() *Timer {
+	return &Timer{}
+}
+
+// After records the duration and returns a channel which is ready immediately
+func (t *Timer) After(d time.Duration) <-chan time.Time {
+	t.mu.Lock()
+	t.delays = append(t.delays, d)
+	t.mu.Unlock()
+
+	c := make(chan time.Time, 1)
+	c <- time.Now()
+	return c
+}
+
+// Delays returns all durations requested so far
+func (t *Timer) Delays() []time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]time.Duration(nil), t.delays...)
+}
+
+// Recorder records attempts of a retryable function
+type Recorder struct {
+	mu     sync.Mutex
+	errors []error
+}
+
+// Wrap returns the retryable function which records every attempt of the given one
+func (r *Recorder) Wrap(retryableFunc retry.RetryableFunc) retry.RetryableFunc {
+	return func() error {
+		err := retryableFunc()
+		r.mu.Lock()
+		r.errors = append(r.errors, err)
+		r.mu.Unlock()
+		return err
+	}
+}
+
+// Attempts returns count of recorded attempts
+func (r *Recorder) Attempts() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.errors)
+}
+
+// Errors returns errors of recorded attempts, nil for successful ones
+func (r *Recorder) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errors...)
+}
+
+// AssertAttempts asserts that err is a retry.Error containing errors of `attempts` failed attempts.
+// A context error appended by a cancelled retry is not counted.
+func AssertAttempts(t testing.TB, err error, attempts int) bool {
+	t.Helper()
+
+	var retryErr retry.Error
+	if !errors.As(err, &retryErr) {
+		t.Errorf("expected retry.Error with %d attempts, got %T: %v", attempts, err, err)
+		return false
+	}
+
+	n := len(retryErr)
+	if n > 0 && isContextError(retryErr[n-1]) {
+		n--
+	}
+	if n != attempts {
+		t.Errorf("expected %d failed attempts, got %d: %v", attempts, n, err)
+		return false
+	}
+	return true
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// Outcome is the result of a retry executed by Run
+type Outcome struct {
+	// Attempts is count of calls of the retryable function
+	Attempts int
+	// Delays chosen between attempts, in order
+	Delays []time.Duration
+	// Elapsed is the virtual time the retry took
+	Elapsed time.Duration
+	// Err returned by the retry
+	Err error
+}
+
+// Run retries the function with the options in virtual time (see `VirtualTimer`) and returns what happened,
+// so the behavior of the options can be asserted in one go:
+//
+//	outcome := retrytest.Run(t, retrytest.ScriptKeepFailing(errTimeout),
+//		retry.Attempts(4),
+//		retry.Delay(100*time.Millisecond),
+//		retry.DelayType(retry.BackOffDelay),
+//	)
+//	assert.Equal(t, 4, outcome.Attempts)
+//	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}, outcome.Delays)
+//	assert.Error(t, outcome.Err)
+//
+// The Timer and Clock of the options are replaced. Invalid options fail the test immediately.
+func Run(t testing.TB, retryableFunc retry.RetryableFunc, opts ...retry.Option) Outcome {
+	t.Helper()
+
+	if err := retry.Validate(opts...); err != nil {
+		t.Fatalf("invalid retry options: %v", err)
+	}
+
+	timer := NewVirtualTimer(time.Time{})
+	recorder := &Recorder{}
+	runOpts := make([]retry.Option, 0, len(opts)+1)
+	runOpts = append(runOpts, opts...)
+	runOpts = append(runOpts, retry.WithClock(timer))
+
+	err := retry.Do(recorder.Wrap(retryableFunc), runOpts...)
+	return Outcome{
+		Attempts: recorder.Attempts(),
+		Delays:   timer.Delays(),
+		Elapsed:  timer.Elapsed(),
+		Err:      err,
+	}
+}
@@ -0,0 +1,73 @@
+package retrytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimerAndRecorder(t *testing.T) {
+	timer := NewTimer()
+	recorder := &Recorder{}
+	testErr := errors.New("test")
+
+	start := time.Now()
+	err := retry.Do(
+		recorder.Wrap(func() error { return testErr }),
+		retry.Attempts(3),
+		retry.Delay(time.Hour),
+		retry.DelayType(retry.FixedDelay),
+		retry.WithTimer(timer),
+	)
+	assert.Less(t, time.Since(start), time.Second, "no real sleep")
+
+	AssertAttempts(t, err, 3)
+	assert.Equal(t, 3, recorder.Attempts())
+	assert.Equal(t, []error{testErr, testErr, testErr}, recorder.Errors())
+	assert.Equal(t, []time.Duration{time.Hour, time.Hour}, timer.Delays())
+}
+
+type mockTB struct {
+	testing.TB
+	failed bool
+}
+
+func (m *mockTB) Helper()                           {}
+func (m *mockTB) Errorf(format string, args ...any) { m.failed = true }
+
+func TestAssertAttempts(t *testing.T) {
+	testErr := errors.New("test")
+
+	mock := &mockTB{TB: t}
+	assert.False(t, AssertAttempts(mock, testErr, 1), "not a retry.Error")
+	assert.False(t, AssertAttempts(mock, retry.Error{testErr, testErr}, 3))
+	assert.True(t, AssertAttempts(mock, retry.Error{testErr, testErr, context.Canceled}, 2))
+	assert.True(t, mock.failed)
+}
+
+func TestRun(t *testing.T) {
+	testErr := errors.New("test")
+	outcome := Run(t, ScriptKeepFailing(testErr),
+		retry.Attempts(4),
+		retry.Delay(100*time.Millisecond),
+		retry.DelayType(retry.BackOffDelay),
+	)
+	assert.Equal(t, 4, outcome.Attempts)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}, outcome.Delays)
+	AssertAttempts(t, outcome.Err, 4)
+
+	outcome = Run(t, Script(testErr), retry.Delay(time.Hour), retry.DelayType(retry.FixedDelay))
+	assert.Equal(t, Outcome{Attempts: 2, Delays: []time.Duration{time.Hour}, Elapsed: time.Hour}, outcome)
+
+	outcome = Run(t, func() error {
+		time.Sleep(time.Millisecond)
+		return nil
+	}, retry.HardAttemptTimeout(time.Minute))
+	assert.NoError(t, outcome.Err, "HardAttemptTimeout runs unchanged in virtual time")
+	assert.Equal(t, 1, outcome.Attempts)
+	assert.Empty(t, outcome.Delays)
+}
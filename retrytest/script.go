@@ -0,0 +1,56 @@
+package retrytest
+
+import (
+	"sync"
+
+	"github.com/avast/retry-go/v4"
+)
+
+// Script returns a retryable function which fails with the given errors in order and then succeeds.
+// A nil error in the script is a successful attempt.
+//
+//	err := retry.Do(
+//		retrytest.Script(errTimeout, errTimeout),
+//		retry.WithTimer(retrytest.NewTimer()),
+//	)
+//	// succeeds on the third attempt
+func Script(errs ...error) retry.RetryableFunc {
+	return script(errs, false)
+}
+
+// ScriptKeepFailing is like Script, but it keeps failing with the last error once the script is exhausted
+func ScriptKeepFailing(errs ...error) retry.RetryableFunc {
+	return script(errs, true)
+}
+
+// ScriptWithData is like Script for retryable functions with data,
+// the value is returned by successful attempts
+func ScriptWithData[T any](value T, errs ...error) retry.RetryableFuncWithData[T] {
+	next := script(errs, false)
+	return func() (T, error) {
+		if err := next(); err != nil {
+			var emptyT T
+			return emptyT, err
+		}
+		return value, nil
+	}
+}
+
+func script(errs []error, keepFailing bool) func() error {
+	var mu sync.Mutex
+	i := 0
+	return func() error {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if i < len(errs) {
+			err := errs[i]
+			i++
+			return err
+		}
+		if keepFailing && len(errs) > 0 {
+			return errs[len(errs)-1]
+		}
+		return nil
+	}
+}
@@ -0,0 +1,43 @@
+package retrytest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScript(t *testing.T) {
+	errA, errB := errors.New("a"), errors.New("b")
+
+	for _, c := range []struct {
+		label    string
+		fn       retry.RetryableFunc
+		attempts int
+		success  bool
+	}{
+		{"succeeds after script", Script(errA, errB), 3, true},
+		{"empty script succeeds", Script(), 1, true},
+		{"keeps failing", ScriptKeepFailing(errA, errB), 5, false},
+		{"unrecoverable stops", Script(errA, retry.Unrecoverable(errB)), 2, false},
+	} {
+		t.Run(c.label, func(t *testing.T) {
+			recorder := &Recorder{}
+			err := retry.Do(
+				recorder.Wrap(c.fn),
+				retry.Attempts(5),
+				retry.WithTimer(NewTimer()),
+			)
+			assert.Equal(t, c.success, err == nil)
+			assert.Equal(t, c.attempts, recorder.Attempts())
+		})
+	}
+
+	v, err := retry.DoWithData(
+		ScriptWithData(42, errA, nil),
+		retry.WithTimer(NewTimer()),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, v)
+}
@@ -0,0 +1,69 @@
+package retrytest
+
+import (
+	"sync"
+	"time"
+)
+
+// VirtualTimer is a retry.Clock of virtual time: every duration requested by After elapses immediately,
+// moving the virtual time forward, and is recorded.
+// Tests of code with long production delays (minutes, hours) finish instantly without changing its options,
+// and everything reading the time of the retry (deadlines, windows, progress) sees the delays pass.
+//
+//	timer := retrytest.NewVirtualTimer(time.Time{})
+//	err := retry.Do(
+//		func() error { ... },
+//		append(productionOptions, retry.WithClock(timer))...,
+//	)
+//	// timer.Elapsed() is the sum of timer.Delays()
+type VirtualTimer struct {
+	mu     sync.Mutex
+	start  time.Time
+	now    time.Time
+	delays []time.Duration
+}
+
+// NewVirtualTimer creates a VirtualTimer starting at the given time, the current time if it is zero
+func NewVirtualTimer(start time.Time) *VirtualTimer {
+	if start.IsZero() {
+		start = time.Now()
+	}
+	return &VirtualTimer{start: start, now: start}
+}
+
+// Now returns the virtual time
+func (v *VirtualTimer) Now() time.Time {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now
+}
+
+// After records the duration, moves the virtual time forward by it
+// and returns a channel which is ready immediately with the new virtual time
+func (v *VirtualTimer) After(d time.Duration) <-chan time.Time {
+	v.mu.Lock()
+	v.delays = append(v.delays, d)
+	if d > 0 {
+		v.now = v.now.Add(d)
+	}
+	now := v.now
+	v.mu.Unlock()
+
+	c := make(chan time.Time, 1)
+	c <- now
+	return c
+}
+
+// Delays returns all durations requested so far
+func (v *VirtualTimer) Delays() []time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return append([]time.Duration(nil), v.delays...)
+}
+
+// Elapsed returns the virtual time elapsed since the start
+func (v *VirtualTimer) Elapsed() time.Duration {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	return v.now.Sub(v.start)
+}
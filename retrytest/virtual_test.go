@@ -0,0 +1,33 @@
+package retrytest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/avast/retry-go/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVirtualTimer(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	timer := NewVirtualTimer(start)
+
+	var states []time.Time
+	begin := time.Now()
+	err := retry.Do(
+		ScriptKeepFailing(errors.New("test")),
+		retry.Attempts(3),
+		retry.Delay(10*time.Minute),
+		retry.DelayType(retry.FixedDelay),
+		retry.OnState(func(state retry.State) { states = append(states, state.NextRunAt) }),
+		retry.WithClock(timer),
+	)
+	assert.Less(t, time.Since(begin), time.Second, "no real sleep")
+	AssertAttempts(t, err, 3)
+
+	assert.Equal(t, []time.Duration{10 * time.Minute, 10 * time.Minute}, timer.Delays())
+	assert.Equal(t, 20*time.Minute, timer.Elapsed())
+	assert.Equal(t, start.Add(20*time.Minute), timer.Now())
+	assert.Equal(t, []time.Time{start.Add(10 * time.Minute), start.Add(20 * time.Minute)}, states, "the retry sees the virtual time")
+}
@@ -0,0 +1,190 @@
+package retry
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule returns the next activation time after the given time.
+// It is compatible with the `Schedule` interface of github.com/robfig/cron.
+type Schedule interface {
+	Next(time.Time) time.Time
+}
+
+// ScheduleDelay is a DelayType which waits until the next activation of the schedule,
+// so retries align to fixed ticks (top of the minute, nightly windows, ...) instead of relative durations.
+// When the schedule never activates again, FixedDelay is used.
+//
+//	retry.Do(
+//		func() error { ... },
+//		retry.DelayType(retry.ScheduleDelay(retry.MustParseCron("*/5 * * * *"))),
+//	)
+func ScheduleDelay(schedule Schedule) DelayTypeFunc {
+	return func(n uint, err error, config *Config) time.Duration {
+		now := config.clock.Now()
+		next := schedule.Next(now)
+		if next.IsZero() {
+			return FixedDelay(n, err, config)
+		}
+		return next.Sub(now)
+	}
+}
+
+// cronSchedule is a parsed standard 5-field cron expression
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+type cronField struct {
+	min, max uint
+}
+
+var (
+	cronMinute = cronField{0, 59}
+	cronHour   = cronField{0, 23}
+	cronDom    = cronField{1, 31}
+	cronMonth  = cronField{1, 12}
+	cronDow    = cronField{0, 7}
+)
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// ParseCron parses a standard 5-field cron expression (minute, hour, day of month, month, day of week)
+// in the local time zone. Fields support `*`, numbers, ranges `a-b`, lists `a,b` and steps `*/n`, `a-b/n`.
+// Day of week is 0-7 where both 0 and 7 are Sunday. Macros like `@hourly` and `@daily` are supported too.
+func ParseCron(expr string) (Schedule, error) {
+	if macro, ok := cronMacros[strings.TrimSpace(expr)]; ok {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("retry: cron expression %q must have 5 fields", expr)
+	}
+
+	var s cronSchedule
+	var err error
+	if s.minute, err = cronMinute.parse(fields[0]); err != nil {
+		return nil, err
+	}
+	if s.hour, err = cronHour.parse(fields[1]); err != nil {
+		return nil, err
+	}
+	if s.dom, err = cronDom.parse(fields[2]); err != nil {
+		return nil, err
+	}
+	if s.month, err = cronMonth.parse(fields[3]); err != nil {
+		return nil, err
+	}
+	if s.dow, err = cronDow.parse(fields[4]); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	s.domStar = fields[2] == "*"
+	s.dowStar = fields[4] == "*"
+
+	return &s, nil
+}
+
+// MustParseCron is like ParseCron but panics if the expression cannot be parsed
+func MustParseCron(expr string) Schedule {
+	s, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func (f cronField) parse(field string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, uint(1)
+		if i := strings.IndexByte(part, '/'); i >= 0 {
+			s, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || s == 0 {
+				return 0, fmt.Errorf("retry: invalid cron step in %q", part)
+			}
+			rangePart, step = part[:i], uint(s)
+		}
+
+		low, high := f.min, f.max
+		if rangePart != "*" {
+			bounds := strings.SplitN(rangePart, "-", 2)
+			l, err := strconv.ParseUint(bounds[0], 10, 8)
+			if err != nil {
+				return 0, fmt.Errorf("retry: invalid cron value %q", part)
+			}
+			low, high = uint(l), uint(l)
+			if len(bounds) == 2 {
+				h, err := strconv.ParseUint(bounds[1], 10, 8)
+				if err != nil {
+					return 0, fmt.Errorf("retry: invalid cron range %q", part)
+				}
+				high = uint(h)
+			} else if step > 1 {
+				high = f.max
+			}
+		}
+
+		if low < f.min || high > f.max || low > high {
+			return 0, fmt.Errorf("retry: cron value %q out of range %d-%d", part, f.min, f.max)
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+
+	return bits, nil
+}
+
+// Next returns the first activation strictly after t, or zero time if there is none within 5 years
+func (s *cronSchedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+
+	return time.Time{}
+}
+
+func (s *cronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCron(t *testing.T) {
+	base := time.Date(2024, time.March, 6, 10, 7, 30, 0, time.UTC) // Wednesday
+
+	for _, c := range []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 6, 10, 8, 0, 0, time.UTC)},
+		{"*/5 * * * *", time.Date(2024, time.March, 6, 10, 10, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, time.March, 6, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.March, 7, 0, 0, 0, 0, time.UTC)},
+		{"30 2 * * 0", time.Date(2024, time.March, 10, 2, 30, 0, 0, time.UTC)},
+		{"30 2 * * 7", time.Date(2024, time.March, 10, 2, 30, 0, 0, time.UTC)},
+		{"0 9-17/4 * * 1-5", time.Date(2024, time.March, 6, 13, 0, 0, 0, time.UTC)},
+		{"0 0 1,15 * *", time.Date(2024, time.March, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 13 * 5", time.Date(2024, time.March, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 0 31 2 *", time.Time{}},
+	} {
+		t.Run(c.expr, func(t *testing.T) {
+			s, err := ParseCron(c.expr)
+			assert.NoError(t, err)
+			assert.Equal(t, c.expected, s.Next(base))
+		})
+	}
+
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := ParseCron(expr)
+		assert.Error(t, err, expr)
+	}
+	assert.Panics(t, func() { MustParseCron("bad") })
+}
+
+type scheduleFunc func(time.Time) time.Time
+
+func (f scheduleFunc) Next(t time.Time) time.Time { return f(t) }
+
+func TestScheduleDelay(t *testing.T) {
+	timer := &recordingTimer{}
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(2),
+		DelayType(ScheduleDelay(scheduleFunc(func(t time.Time) time.Time { return t.Add(time.Hour) }))),
+		WithTimer(timer),
+	)
+	assert.Error(t, err)
+	assert.Len(t, timer.delays, 1)
+	assert.InDelta(t, float64(time.Hour), float64(timer.delays[0]), float64(time.Second))
+
+	config := newDefaultRetryConfig()
+	config.delay = time.Second
+	never := ScheduleDelay(scheduleFunc(func(time.Time) time.Time { return time.Time{} }))
+	assert.Equal(t, time.Second, never(0, nil, config))
+}
@@ -0,0 +1,199 @@
+package retry
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrSchedulerClosed is returned by `Scheduler.Schedule` after the scheduler was closed
+var ErrSchedulerClosed = errors.New("retry: scheduler closed")
+
+// JobStatus represents the state of a job scheduled by a Scheduler
+type JobStatus int
+
+const (
+	// JobPending is waiting in the queue for a free worker
+	JobPending JobStatus = iota
+	// JobRunning is being retried by a worker
+	JobRunning
+	// JobSucceeded finished successfully
+	JobSucceeded
+	// JobFailed finished with an error
+	JobFailed
+)
+
+// String returns the name of the status
+func (s JobStatus) String() string {
+	switch s {
+	case JobPending:
+		return "pending"
+	case JobRunning:
+		return "running"
+	case JobSucceeded:
+		return "succeeded"
+	case JobFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// Job is a handle of a retryable function scheduled by a Scheduler
+type Job struct {
+	retryableFunc RetryableFunc
+	opts          []Option
+	done          chan struct{}
+
+	mu     sync.Mutex
+	status JobStatus
+	err    error
+}
+
+// Status returns current status of the job
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.status
+}
+
+// Done returns a channel which is closed when the job is finished
+func (j *Job) Done() <-chan struct{} {
+	return j.done
+}
+
+// Wait blocks until the job is finished and returns the error of the retry (if any)
+func (j *Job) Wait() error {
+	<-j.done
+	return j.Err()
+}
+
+// Err returns the error of a finished job, nil otherwise
+func (j *Job) Err() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.err
+}
+
+func (j *Job) setStatus(status JobStatus, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.status = status
+	j.err = err
+}
+
+// Scheduler runs retry loops of scheduled jobs in the background on a bounded pool of workers.
+//
+//	s := retry.NewScheduler(8, retry.Attempts(5))
+//	defer s.Close()
+//
+//	job, err := s.Schedule(func() error { ... }, retry.Delay(time.Second))
+//	...
+//	err = job.Wait()
+type Scheduler struct {
+	opts []Option
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	queue   []*Job
+	running int
+	closed  bool
+
+	wg sync.WaitGroup
+}
+
+// NewScheduler starts a Scheduler with given count of workers (at least one).
+// Options are applied to every job before the options of the job itself.
+func NewScheduler(workers int, opts ...Option) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+
+	s := &Scheduler{opts: opts}
+	s.cond = sync.NewCond(&s.mu)
+	s.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go s.worker()
+	}
+
+	return s
+}
+
+// Schedule queues the retryable function to be retried by a worker
+func (s *Scheduler) Schedule(retryableFunc RetryableFunc, opts ...Option) (*Job, error) {
+	jobOpts := make([]Option, 0, len(s.opts)+len(opts))
+	jobOpts = append(jobOpts, s.opts...)
+	jobOpts = append(jobOpts, opts...)
+
+	job := &Job{
+		retryableFunc: retryableFunc,
+		opts:          jobOpts,
+		done:          make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, ErrSchedulerClosed
+	}
+	s.queue = append(s.queue, job)
+	s.cond.Signal()
+
+	return job, nil
+}
+
+// QueueDepth returns count of jobs waiting for a free worker
+func (s *Scheduler) QueueDepth() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.queue)
+}
+
+// Running returns count of jobs being retried right now
+func (s *Scheduler) Running() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running
+}
+
+// Close stops accepting new jobs and waits until all queued jobs are finished.
+// Use `retry.Context` to cancel long running jobs.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.cond.Broadcast()
+	s.mu.Unlock()
+
+	s.wg.Wait()
+}
+
+func (s *Scheduler) worker() {
+	defer s.wg.Done()
+
+	for {
+		s.mu.Lock()
+		for len(s.queue) == 0 && !s.closed {
+			s.cond.Wait()
+		}
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		job := s.queue[0]
+		s.queue[0] = nil
+		s.queue = s.queue[1:]
+		s.running++
+		s.mu.Unlock()
+
+		job.setStatus(JobRunning, nil)
+		if err := Do(job.retryableFunc, job.opts...); err != nil {
+			job.setStatus(JobFailed, err)
+		} else {
+			job.setStatus(JobSucceeded, nil)
+		}
+
+		s.mu.Lock()
+		s.running--
+		s.mu.Unlock()
+		close(job.done)
+	}
+}
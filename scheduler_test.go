@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduler(t *testing.T) {
+	s := NewScheduler(2, Delay(time.Nanosecond))
+
+	var attempts int32
+	ok, err := s.Schedule(func() error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("test")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	failed, err := s.Schedule(func() error { return errors.New("test") }, Attempts(2))
+	assert.NoError(t, err)
+
+	assert.NoError(t, ok.Wait())
+	assert.Equal(t, JobSucceeded, ok.Status())
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+
+	assert.Error(t, failed.Wait())
+	assert.Len(t, failed.Err(), 2)
+	assert.Equal(t, JobFailed, failed.Status())
+
+	s.Close()
+	_, err = s.Schedule(func() error { return nil })
+	assert.ErrorIs(t, err, ErrSchedulerClosed)
+}
+
+func TestSchedulerQueueDepth(t *testing.T) {
+	s := NewScheduler(1)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	blocking, _ := s.Schedule(func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+
+	queued, _ := s.Schedule(func() error { return nil })
+	assert.Equal(t, 1, s.QueueDepth())
+	assert.Equal(t, 1, s.Running())
+	assert.Equal(t, JobRunning, blocking.Status())
+	assert.Equal(t, JobPending, queued.Status())
+	assert.Equal(t, "pending", queued.Status().String())
+
+	close(release)
+	s.Close()
+	assert.Equal(t, JobSucceeded, queued.Status())
+	assert.Equal(t, 0, s.QueueDepth())
+}
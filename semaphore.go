@@ -0,0 +1,62 @@
+package retry
+
+import "context"
+
+// Semaphore bounds the count of attempts running at the same time,
+// *semaphore.Weighted of golang.org/x/sync satisfies Semaphore.
+type Semaphore interface {
+	Acquire(ctx context.Context, n int64) error
+	Release(n int64)
+}
+
+// WithSemaphore acquires the semaphore (with weight 1) around every attempt, so all retry loops sharing it
+// have an upper bound on in-flight calls, e.g. to a fragile dependency. Delays between attempts don't hold it.
+// When the context is done while waiting for the semaphore, the attempt fails with the context error.
+//
+//	var sem = retry.NewSemaphore(10)
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.WithSemaphore(sem),
+//	)
+func WithSemaphore(sem Semaphore) Option {
+	return func(c *Config) {
+		c.describe("WithSemaphore", sem)
+		if sem == nil {
+			c.invalid("WithSemaphore must not be nil")
+			return
+		}
+		c.extend().semaphore = sem
+	}
+}
+
+// NewSemaphore returns a plain Semaphore with given count of slots (at least one).
+// Weights acquired at once must not exceed the count.
+func NewSemaphore(n int) Semaphore {
+	if n < 1 {
+		n = 1
+	}
+	return make(chanSemaphore, n)
+}
+
+type chanSemaphore chan struct{}
+
+func (s chanSemaphore) Acquire(ctx context.Context, n int64) error {
+	for i := int64(0); i < n; i++ {
+		select {
+		case s <- struct{}{}:
+		case <-ctx.Done():
+			s.Release(i)
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+func (s chanSemaphore) Release(n int64) {
+	for i := int64(0); i < n; i++ {
+		<-s
+	}
+}
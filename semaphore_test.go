@@ -0,0 +1,63 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSemaphore(t *testing.T) {
+	sem := NewSemaphore(2)
+
+	var running, maxRunning int32
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			calls := 0
+			err := Do(func() error {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				mu.Lock()
+				if n > maxRunning {
+					maxRunning = n
+				}
+				mu.Unlock()
+
+				time.Sleep(time.Millisecond)
+				calls++
+				if calls < 2 {
+					return errors.New("test")
+				}
+				return nil
+			}, Delay(0), WithSemaphore(sem))
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, maxRunning, int32(2))
+}
+
+func TestWithSemaphoreCancelled(t *testing.T) {
+	sem := NewSemaphore(1)
+	assert.NoError(t, sem.Acquire(context.Background(), 1))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	called := false
+	err := Do(func() error {
+		called = true
+		return nil
+	}, Context(ctx), WithSemaphore(sem))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.False(t, called)
+}
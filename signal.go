@@ -0,0 +1,67 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// SignalError is the error of a retry stopped by a signal, see `WithSignals`.
+// It matches context.Canceled with errors.Is.
+type SignalError struct {
+	Signal os.Signal
+}
+
+func (e *SignalError) Error() string {
+	return fmt.Sprintf("retry: stopped by signal %v", e.Signal)
+}
+
+// Is makes errors.Is(err, context.Canceled) true
+func (e *SignalError) Is(target error) bool {
+	return target == context.Canceled
+}
+
+// WithSignals stops the retry when the process receives one of the signals, e.g. os.Interrupt
+// or syscall.SIGTERM, so retry loops don't delay a clean shutdown: sleeping between attempts ends
+// and no further attempt starts. The in-flight attempt is allowed to finish and its success is returned;
+// to interrupt it as well, pass a context cancelled by the signals (see signal.NotifyContext) to `DoContext`.
+// The retry fails with the context error, which is a *SignalError since go1.20.
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.WithSignals(os.Interrupt, syscall.SIGTERM),
+//	)
+func WithSignals(signals ...os.Signal) Option {
+	return func(c *Config) {
+		c.describe("WithSignals", signals)
+		if len(signals) == 0 {
+			c.invalid("WithSignals requires at least one signal")
+			return
+		}
+		c.extend().signals = signals
+	}
+}
+
+// notifySignals replaces the context of the retry by one cancelled by the signals,
+// the returned func stops the notification
+func (c *Config) notifySignals() (stop func()) {
+	ctx, cancel := withCancelCause(c.context)
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, c.ext.signals...)
+	go func() {
+		select {
+		case sig := <-received:
+			cancel(&SignalError{Signal: sig})
+		case <-ctx.Done():
+		}
+	}()
+
+	c.context = ctx
+	return func() {
+		signal.Stop(received)
+		cancel(nil)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !windows && !plan9 && !js && !wasip1
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSignals(t *testing.T) {
+	var count int
+	start := time.Now()
+	err := Do(
+		func() error {
+			count++
+			if count == 1 {
+				// the in-flight attempt finishes
+				assert.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+				time.Sleep(10 * time.Millisecond)
+			}
+			return errors.New("test")
+		},
+		Attempts(3),
+		Delay(time.Hour),
+		WithSignals(syscall.SIGUSR1),
+	)
+	assert.Equal(t, 1, count)
+	assert.Less(t, time.Since(start), time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	var signalErr *SignalError
+	if errors.As(err, &signalErr) { // since go1.20
+		assert.Equal(t, syscall.SIGUSR1, signalErr.Signal)
+	}
+
+	assert.ErrorIs(t, Validate(WithSignals()), ErrInvalidOption)
+}
@@ -0,0 +1,32 @@
+package retry
+
+// Singleflight deduplicates concurrent calls with the same key,
+// *singleflight.Group of golang.org/x/sync satisfies Singleflight.
+type Singleflight interface {
+	Do(key string, fn func() (interface{}, error)) (v interface{}, err error, shared bool)
+}
+
+// WithSingleflight makes concurrent retries with the same key in the group share one retry loop:
+// the first caller runs the retry and the callers arriving in the meantime wait for it and receive its result,
+// so N goroutines don't hammer a down dependency independently.
+//
+// The shared retry runs with the options (including the Context) of the caller which started it.
+// Callers sharing a key must retry functions of the same result type (see `DoWithData`).
+//
+//	var group singleflight.Group
+//
+//	user, err := retry.DoWithData(
+//		func() (*User, error) { return fetchUser(id) },
+//		retry.WithSingleflight("user:"+id, &group),
+//	)
+func WithSingleflight(key string, group Singleflight) Option {
+	return func(c *Config) {
+		c.describe("WithSingleflight", key)
+		if group == nil {
+			c.invalid("WithSingleflight group must not be nil")
+			return
+		}
+		c.extend().singleflight = group
+		c.extend().singleflightKey = key
+	}
+}
@@ -0,0 +1,43 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sharedGroup runs the first call of a key and shares its result with the later ones
+type sharedGroup struct {
+	results map[string]interface{}
+}
+
+func (g *sharedGroup) Do(key string, fn func() (interface{}, error)) (interface{}, error, bool) {
+	if v, ok := g.results[key]; ok {
+		return v, nil, true
+	}
+	v, err := fn()
+	g.results[key] = v
+	return v, err, false
+}
+
+func TestWithSingleflight(t *testing.T) {
+	group := &sharedGroup{results: map[string]interface{}{}}
+	calls := 0
+	fetch := func() (int, error) {
+		calls++
+		if calls < 2 {
+			return 0, errors.New("test")
+		}
+		return 42, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		v, err := DoWithData(fetch, Delay(0), WithSingleflight("key", group))
+		assert.NoError(t, err)
+		assert.Equal(t, 42, v)
+	}
+	assert.Equal(t, 2, calls)
+
+	assert.ErrorIs(t, Validate(WithSingleflight("key", nil)), ErrInvalidOption)
+}
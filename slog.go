@@ -0,0 +1,42 @@
+//go:build go1.21
+
+package retry
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// LogValue implements slog.LogValuer, so the attempt is logged as a group
+// (e.g. `attempt.number=3 attempt.duration=12ms attempt.delay=800ms attempt.err=...`)
+func (a Attempt) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, 5)
+	attrs = append(attrs,
+		slog.Uint64("number", uint64(a.Number)),
+		slog.Time("start", a.Start),
+		slog.Duration("duration", a.Duration),
+	)
+	if a.Delay > 0 {
+		attrs = append(attrs, slog.Duration("delay", a.Delay))
+	}
+	if a.Err != nil {
+		attrs = append(attrs, slog.String("err", a.Err.Error()))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, so the errors are logged as a group
+// with the count of attempts and the error of every attempt
+// (e.g. `err.attempts=2 err.errors.1=... err.errors.2=...`) instead of one multi-line string
+func (e Error) LogValue() slog.Value {
+	errs := make([]slog.Attr, 0, len(e))
+	for i, err := range e {
+		if err != nil {
+			errs = append(errs, slog.String(strconv.Itoa(i+1), err.Error()))
+		}
+	}
+	return slog.GroupValue(
+		slog.Int("attempts", len(e)),
+		slog.Attr{Key: "errors", Value: slog.GroupValue(errs...)},
+	)
+}
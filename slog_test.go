@@ -0,0 +1,33 @@
+//go:build go1.21
+
+package retry
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "start" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+
+	logger.Info("failed", "attempt", Attempt{Number: 3, Duration: 12 * time.Millisecond, Err: errors.New("test"), Delay: 800 * time.Millisecond})
+	assert.Equal(t, "level=INFO msg=failed attempt.number=3 attempt.duration=12ms attempt.delay=800ms attempt.err=test\n", buf.String())
+
+	buf.Reset()
+	err := Do(func() error { return errors.New("test") }, Attempts(2), Delay(0))
+	logger.Error("failed", "err", err)
+	assert.Equal(t, "level=ERROR msg=failed err.attempts=2 err.errors.1=test err.errors.2=test\n", buf.String())
+}
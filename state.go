@@ -0,0 +1,71 @@
+package retry
+
+import "time"
+
+// State is a serializable snapshot of a retry loop.
+// Persist it (e.g. as JSON) via `OnState` and continue later with `Resume`,
+// so a job survives a process restart without restarting the backoff from zero.
+type State struct {
+	// Attempt is count of attempts already made
+	Attempt uint `json:"attempt"`
+	// NextRunAt is the time of the next planned attempt
+	NextRunAt time.Time `json:"next_run_at"`
+	// LastError is the message of the last error
+	LastError string `json:"last_error,omitempty"`
+}
+
+func newState(now time.Time, attempt uint, delay time.Duration, err error) State {
+	state := State{
+		Attempt:   attempt,
+		NextRunAt: now.Add(delay),
+	}
+	if err != nil {
+		state.LastError = err.Error()
+	}
+	return state
+}
+
+// OnState function callback is called after each failed attempt which will be retried,
+// right before sleeping, with the current State of the retry loop
+//
+//	retry.Do(
+//		func() error { ... },
+//		retry.OnState(func(state retry.State) {
+//			b, _ := json.Marshal(state)
+//			db.Save(jobID, b)
+//		}),
+//	)
+func OnState(onState func(State)) Option {
+	if onState == nil {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("OnState", onState)
+		c.extend().onState = onState
+	}
+}
+
+// Resume continues a retry loop from a State saved by `OnState`.
+// It waits until `state.NextRunAt`, counts already made attempts against `Attempts`
+// and continues the backoff where it stopped. At least one attempt is always made.
+func Resume(state State, retryableFunc RetryableFunc, opts ...Option) error {
+	_, err := ResumeWithData(state, func() (any, error) {
+		return nil, retryableFunc()
+	}, opts...)
+	return err
+}
+
+// ResumeWithData is `Resume` for retryable functions with data
+func ResumeWithData[T any](state State, retryableFunc RetryableFuncWithData[T], opts ...Option) (T, error) {
+	resumeOpts := make([]Option, 0, len(opts)+1)
+	resumeOpts = append(resumeOpts, opts...)
+	resumeOpts = append(resumeOpts, resume(state))
+	return DoWithData(retryableFunc, resumeOpts...)
+}
+
+func resume(state State) Option {
+	return func(c *Config) {
+		c.extend().startAttempt = state.Attempt
+		c.extend().startAt = state.NextRunAt
+	}
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateResume(t *testing.T) {
+	var saved []byte
+	testErr := errors.New("test")
+
+	err := Do(
+		func() error { return testErr },
+		Attempts(5),
+		Delay(time.Millisecond),
+		DelayType(BackOffDelay),
+		OnState(func(state State) {
+			if state.Attempt == 2 {
+				saved, _ = json.Marshal(state)
+			}
+		}),
+		RetryIf(func(err error) bool { return len(saved) == 0 }),
+	)
+	assert.Error(t, err)
+
+	var state State
+	assert.NoError(t, json.Unmarshal(saved, &state))
+	assert.Equal(t, uint(2), state.Attempt)
+	assert.Equal(t, "test", state.LastError)
+
+	timer := &recordingTimer{}
+	attempts := 0
+	err = Resume(
+		state,
+		func() error {
+			attempts++
+			return testErr
+		},
+		Attempts(5),
+		Delay(time.Millisecond),
+		DelayType(BackOffDelay),
+		WithTimer(timer),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts, "remaining attempts")
+	assert.Len(t, timer.delays, 3)
+	assert.LessOrEqual(t, timer.delays[0], 2*time.Millisecond, "wait until next run")
+	assert.Equal(t, []time.Duration{4 * time.Millisecond, 8 * time.Millisecond}, timer.delays[1:], "backoff continues")
+}
+
+func TestResumeExhausted(t *testing.T) {
+	attempts := 0
+	err := Resume(
+		State{Attempt: 3},
+		func() error {
+			attempts++
+			return errors.New("test")
+		},
+		Attempts(3),
+		Delay(time.Nanosecond),
+	)
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
@@ -0,0 +1,35 @@
+package retry
+
+import "time"
+
+// StatsCollector collects metrics of retries, e.g. to export them to Prometheus, expvar or OpenTelemetry,
+// without the library depending on any metrics system.
+// The methods are called synchronously from the retrying goroutine, so they should be cheap;
+// a collector shared by concurrent retries must be safe for concurrent use.
+type StatsCollector interface {
+	// AttemptStarted is called before every attempt
+	AttemptStarted()
+	// AttemptFailed is called after every failed attempt with its error and duration
+	AttemptFailed(err error, d time.Duration)
+	// Finished is called once the retry returns with the count of attempts executed by it
+	Finished(success bool, attempts uint)
+}
+
+// WithStats sets the StatsCollector of the retry
+//
+//	retry.Do(
+//		func() error {
+//			...
+//		},
+//		retry.WithStats(collector),
+//	)
+func WithStats(stats StatsCollector) Option {
+	return func(c *Config) {
+		c.describe("WithStats", stats)
+		if stats == nil {
+			c.invalid("WithStats must not be nil")
+			return
+		}
+		c.extend().stats = stats
+	}
+}
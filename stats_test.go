@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testStats struct {
+	started  int
+	failed   []error
+	success  bool
+	attempts uint
+	finished int
+}
+
+func (s *testStats) AttemptStarted() { s.started++ }
+
+func (s *testStats) AttemptFailed(err error, d time.Duration) {
+	s.failed = append(s.failed, err)
+}
+
+func (s *testStats) Finished(success bool, attempts uint) {
+	s.finished++
+	s.success = success
+	s.attempts = attempts
+}
+
+func TestWithStats(t *testing.T) {
+	errTest := errors.New("test")
+
+	stats := &testStats{}
+	calls := 0
+	err := Do(func() error {
+		calls++
+		if calls < 3 {
+			return errTest
+		}
+		return nil
+	}, Delay(0), WithStats(stats))
+	assert.NoError(t, err)
+	assert.Equal(t, &testStats{started: 3, failed: []error{errTest, errTest}, success: true, attempts: 3, finished: 1}, stats)
+
+	stats = &testStats{}
+	_, err = DoWithData(func() (int, error) { return 0, errTest }, Attempts(2), Delay(0), WithStats(stats))
+	assert.Error(t, err)
+	assert.Equal(t, &testStats{started: 2, failed: []error{errTest, errTest}, success: false, attempts: 2, finished: 1}, stats)
+
+	assert.ErrorIs(t, Validate(WithStats(nil)), ErrInvalidOption)
+}
@@ -0,0 +1,92 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Stepper is the retry loop turned inside out for frameworks owning their own event loop,
+// which cannot block inside `Do`: the caller invokes the function itself and reports the error of every attempt to next,
+// which returns the delay to wait before the next attempt, or false when there should be no further attempt
+// (the attempt succeeded, the error is not retryable, attempts are exhausted, the context is done or stop was called).
+// Delays are computed as by `Do` (DelayType, RetryAfter hints, MaxDelay, ImmediateFirstRetry) and OnRetry is called,
+// but nothing sleeps: waiting is up to the caller. A panic of a hook stops the steps.
+// An invalid option is returned as an ErrInvalidOption error, next then always returns false.
+//
+//	next, stop, err := retry.Stepper(retry.Attempts(5))
+//	if err != nil {
+//		return err
+//	}
+//	defer stop()
+//
+//	for {
+//		err := send()
+//		d, ok := next(err)
+//		if !ok {
+//			return err
+//		}
+//		loop.Schedule(d)...
+//	}
+func Stepper(opts ...Option) (next func(err error) (time.Duration, bool), stop func(), err error) {
+	config := newRetryConfig(opts)
+
+	var mu sync.Mutex
+	n := config.ext.startAttempt
+	stopped := config.ext.err != nil
+
+	// step classifies the error of the attempt and picks the delay, calling the hooks
+	step := func(err error) (time.Duration, bool) {
+		// a custom RetryIf may retry Unrecoverable errors, as in Do
+		if !config.callRetryIf(err) {
+			if !IsRecoverable(err) {
+				config.ext.onAbort(err, err, n+1)
+			}
+			return 0, false
+		}
+		if config.ext.unrecoverableIf(err) {
+			config.ext.onAbort(err, err, n+1)
+			return 0, false
+		}
+		if config.attempts != 0 && n+1 >= config.attempts {
+			return 0, false
+		}
+
+		config.callOnRetry(n, err)
+		d := delay(config, n, err)
+		if d == StopDelay {
+			return 0, false
+		}
+		if config.ext.immediateFirstRetry && n == config.ext.startAttempt {
+			d = 0
+		}
+		return d, true
+	}
+
+	next = func(err error) (time.Duration, bool) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err == nil || stopped || config.context.Err() != nil {
+			stopped = true
+			return 0, false
+		}
+
+		var d time.Duration
+		var ok bool
+		// a panic of a hook stops the steps as it stops Do
+		if containHookPanic(func() { d, ok = step(err) }) != nil || !ok {
+			stopped = true
+			return 0, false
+		}
+		n++
+		return d, true
+	}
+
+	stop = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		stopped = true
+	}
+
+	return next, stop, config.ext.err
+}
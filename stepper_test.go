@@ -0,0 +1,75 @@
+package retry
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepper(t *testing.T) {
+	next, stop, err := Stepper(Attempts(3), Delay(10*time.Millisecond), DelayType(BackOffDelay))
+	assert.NoError(t, err)
+	defer stop()
+
+	errTest := errors.New("test")
+	var delays []time.Duration
+	for {
+		d, ok := next(errTest)
+		if !ok {
+			break
+		}
+		delays = append(delays, d)
+	}
+	assert.Equal(t, []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}, delays)
+
+	_, ok := next(errTest)
+	assert.False(t, ok, "stays stopped")
+}
+
+func TestStepperStop(t *testing.T) {
+	next, stop, _ := Stepper(Attempts(0), Delay(0), DelayType(FixedDelay))
+
+	d, ok := next(errors.New("test"))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), d)
+
+	_, ok = next(Unrecoverable(errors.New("test")))
+	assert.False(t, ok)
+
+	next, stop, _ = Stepper(Attempts(0), Delay(0))
+	_, ok = next(nil)
+	assert.False(t, ok, "success ends the steps")
+
+	next, stop, _ = Stepper(Attempts(0), Delay(0))
+	stop()
+	_, ok = next(errors.New("test"))
+	assert.False(t, ok)
+}
+
+func TestStepperInvalidOption(t *testing.T) {
+	next, stop, err := Stepper(Attempts(3), MaxDelay(-1))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	defer stop()
+
+	_, ok := next(errors.New("test"))
+	assert.False(t, ok, "an invalid config doesn't step")
+}
+
+func TestStepperRetryIfUnrecoverable(t *testing.T) {
+	next, stop, _ := Stepper(Attempts(3), Delay(0), RetryIf(func(err error) bool { return true }))
+	defer stop()
+
+	_, ok := next(Unrecoverable(errors.New("test")))
+	assert.True(t, ok, "RetryIf decides about Unrecoverable errors as in Do")
+
+	var aborted error
+	next, stop, _ = Stepper(Attempts(3), Delay(0), OnAbort(func(reason error, lastErr error, n uint) { aborted = reason }))
+	defer stop()
+
+	errTest := Unrecoverable(errors.New("test"))
+	_, ok = next(errTest)
+	assert.False(t, ok)
+	assert.Equal(t, errTest, aborted)
+}
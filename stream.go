@@ -0,0 +1,28 @@
+package retry
+
+// DoWithStream is `DoWithData` sending the result of every attempt to results, even when the attempt fails,
+// so a pipeline can use partial data while the retry continues. The result of the successful attempt is the last one sent.
+// Sending blocks until the result is received or the context of the retry is done.
+// results is closed when the retry ends, so it can be ranged over.
+//
+//	results := make(chan []Item)
+//	go func() {
+//		for items := range results {
+//			index(items)
+//		}
+//	}()
+//	err := retry.DoWithStream(fetchItems, results)
+func DoWithStream[T any](retryableFunc RetryableFuncWithData[T], results chan<- T, opts ...Option) error {
+	defer close(results)
+
+	config := newRetryConfig(opts)
+	_, err := do[T](config, callerFuncWithData[T](func() (T, error) {
+		t, err := retryableFunc()
+		select {
+		case results <- t:
+		case <-config.context.Done():
+		}
+		return t, err
+	}))
+	return err
+}
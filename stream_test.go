@@ -0,0 +1,52 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoWithStream(t *testing.T) {
+	results := make(chan []int)
+	received := make(chan [][]int)
+	go func() {
+		var all [][]int
+		for r := range results {
+			all = append(all, r)
+		}
+		received <- all
+	}()
+
+	var count int
+	err := DoWithStream(
+		func() ([]int, error) {
+			count++
+			if count < 3 {
+				return []int{count}, errors.New("partial")
+			}
+			return []int{1, 2, 3}, nil
+		},
+		results,
+		Delay(0),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]int{{1}, {2}, {1, 2, 3}}, <-received)
+}
+
+func TestDoWithStreamContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	results := make(chan int) // nobody receives
+	err := DoWithStream(
+		func() (int, error) { return 1, errors.New("test") },
+		results,
+		Context(ctx),
+	)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	_, open := <-results
+	assert.False(t, open)
+}
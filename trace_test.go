@@ -0,0 +1,26 @@
+package retry
+
+import (
+	"bytes"
+	"errors"
+	"runtime/trace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceRegions(t *testing.T) {
+	if trace.IsEnabled() {
+		t.Skip("tracing is already enabled")
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(t, trace.Start(&buf))
+	err := Do(func() error { return errors.New("test") }, Attempts(2), Delay(0))
+	trace.Stop()
+
+	assert.Error(t, err)
+	for _, s := range []string{"retry.attempt", "retry.sleep", "attempt #2 failed: test"} {
+		assert.True(t, bytes.Contains(buf.Bytes(), []byte(s)), s)
+	}
+}
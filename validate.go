@@ -0,0 +1,27 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidOption is wrapped by the errors reporting invalid options, e.g. `MaxJitter(-1)` or `Context(nil)`.
+// Invalid options are not applied and the retry returns the error without executing the retried function.
+var ErrInvalidOption = errors.New("retry: invalid option")
+
+// Validate checks the options without executing anything and returns the first invalid option error
+// (wrapping ErrInvalidOption), so misconfigured policies can be rejected when they are built.
+//
+//	if err := retry.Validate(opts...); err != nil {
+//		return fmt.Errorf("retry policy: %w", err)
+//	}
+func Validate(opts ...Option) error {
+	return newRetryConfig(opts).ext.err
+}
+
+// invalid records that an option is invalid, only the first invalid option is reported
+func (c *Config) invalid(format string, args ...any) {
+	if c.ext.err == nil {
+		c.extend().err = fmt.Errorf("%w: "+format, append([]any{ErrInvalidOption}, args...)...)
+	}
+}
@@ -0,0 +1,61 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate())
+	assert.NoError(t, Validate(Attempts(3), Delay(0), MaxJitter(0), Context(context.Background())))
+
+	//nolint:staticcheck // nil context is what is tested
+	for _, opt := range []Option{
+		Delay(-1),
+		InitialDelay(-1),
+		MaxDelay(-time.Second),
+		MaxJitter(-1),
+		Context(nil),
+		WithTimer(nil),
+		WithClock(nil),
+		AbortIfNoProgress(-1),
+		AllowedWindow(-time.Hour, time.Hour),
+	} {
+		assert.ErrorIs(t, Validate(opt), ErrInvalidOption)
+	}
+
+	err := Validate(MaxJitter(-1), Delay(-1))
+	assert.EqualError(t, err, "retry: invalid option: MaxJitter must not be negative, got -1ns")
+}
+
+func TestInvalidOptionIsNotExecuted(t *testing.T) {
+	calls := 0
+	err := Do(func() error {
+		calls++
+		return errors.New("test")
+	}, MaxJitter(-1))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	assert.Equal(t, 0, calls)
+
+	//nolint:staticcheck // nil context is what is tested
+	err = DoContext(nil, func(ctx context.Context) error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	assert.Equal(t, 0, calls)
+
+	_, err = Plan(Delay(-1))
+	assert.ErrorIs(t, err, ErrInvalidOption)
+
+	err = NewRetrier(WithTimer(nil)).Do(func() error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, ErrInvalidOption)
+	assert.Equal(t, 0, calls)
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrHardAttemptTimeout is the error (wrapped) of an attempt abandoned by `HardAttemptTimeout`
+var ErrHardAttemptTimeout = errors.New("retry: attempt abandoned")
+
+// HardAttemptTimeout runs every attempt in a new goroutine and abandons it when it doesn't return within d,
+// so the retry makes progress past a wedged call of a legacy function which can't take a context.
+// The abandoned attempt fails with an error wrapping ErrHardAttemptTimeout (retried like other errors)
+// and keeps running in the background, its result is discarded. Attempts are abandoned also when the context is done.
+// The timeout is measured in real time, also with WithTimer or WithClock, which time only the delays between attempts.
+//
+// Prefer passing a context to the function (see `DoContext`) when it is possible: abandoned goroutines
+// are leaked until the call returns, and panics of the function can't be recovered by the caller of the retry.
+//
+//	retry.Do(
+//		func() error {
+//			return legacyClient.Call()
+//		},
+//		retry.HardAttemptTimeout(5*time.Second),
+//	)
+func HardAttemptTimeout(d time.Duration) Option {
+	return func(c *Config) {
+		c.describe("HardAttemptTimeout", d)
+		if d <= 0 {
+			c.invalid("HardAttemptTimeout must be positive, got %v", d)
+			return
+		}
+		c.extend().hardAttemptTimeout = d
+	}
+}
+
+type attemptResult[T any] struct {
+	t   T
+	err error
+}
+
+// call calls the retryable function, in a goroutine abandoned after the HardAttemptTimeout if there is one
+func call[T any, F caller[T]](config *Config, retryableFunc F) (T, error) {
+	if config.ext.hardAttemptTimeout <= 0 {
+		return retryableFunc.call()
+	}
+
+	results := make(chan attemptResult[T], 1) // the abandoned goroutine doesn't block on it
+	go func() {
+		t, err := retryableFunc.call()
+		results <- attemptResult[T]{t, err}
+	}()
+
+	// the watchdog runs in real time, test timers (e.g. of retrytest) elapse delays at once and would abandon every attempt
+	timer := time.NewTimer(config.ext.hardAttemptTimeout)
+	defer timer.Stop()
+
+	var emptyT T
+	select {
+	case result := <-results:
+		return result.t, result.err
+	case <-timer.C:
+		return emptyT, fmt.Errorf("%w after %v", ErrHardAttemptTimeout, config.ext.hardAttemptTimeout)
+	case <-config.context.Done():
+		return emptyT, contextCause(config.context)
+	}
+}
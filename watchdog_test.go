@@ -0,0 +1,72 @@
+package retry
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHardAttemptTimeout(t *testing.T) {
+	wedged := make(chan struct{})
+	defer close(wedged)
+
+	var count int32
+	err := Do(
+		func() error {
+			if atomic.AddInt32(&count, 1) == 1 {
+				<-wedged // ignores any timeout
+			}
+			return nil
+		},
+		Attempts(2),
+		Delay(0),
+		HardAttemptTimeout(10*time.Millisecond),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&count))
+
+	err = Do(
+		func() error {
+			<-wedged
+			return nil
+		},
+		Attempts(1),
+		HardAttemptTimeout(time.Millisecond),
+	)
+	assert.ErrorIs(t, err, ErrHardAttemptTimeout)
+	assert.EqualError(t, err, "All attempts fail:\n#1: retry: attempt abandoned after 1ms")
+
+	testErr := errors.New("test")
+	err = Do(
+		func() error { return testErr },
+		Attempts(1),
+		HardAttemptTimeout(time.Hour),
+	)
+	assert.ErrorIs(t, err, testErr)
+
+	assert.ErrorIs(t, Validate(HardAttemptTimeout(0)), ErrInvalidOption)
+}
+
+func TestHardAttemptTimeoutTimer(t *testing.T) {
+	timer := &recordingTimer{}
+	var count int32
+	err := Do(
+		func() error {
+			atomic.AddInt32(&count, 1)
+			time.Sleep(time.Millisecond)
+			return errors.New("test")
+		},
+		Attempts(3),
+		Delay(time.Second),
+		DelayType(FixedDelay),
+		HardAttemptTimeout(time.Minute),
+		WithTimer(timer),
+	)
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrHardAttemptTimeout, "an instant timer doesn't abandon attempts")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&count))
+	assert.Equal(t, []time.Duration{time.Second, time.Second}, timer.delays, "the watchdog isn't a delay")
+}
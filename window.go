@@ -0,0 +1,77 @@
+package retry
+
+import "time"
+
+const day = 24 * time.Hour
+
+// window is a daily time window, start and end are offsets since local midnight
+type window struct {
+	start, end time.Duration
+}
+
+// AllowedWindow allows attempts only within a daily time window in local time, given as offsets since midnight.
+// Attempts falling outside of the window (including the first one) are deferred until the window opens.
+// The window may span midnight (start > end). Use the option several times to allow several windows.
+//
+// attempt only between 22:00 and 06:00 example:
+//
+//	retry.Do(
+//		func() error { ... },
+//		retry.AllowedWindow(22*time.Hour, 6*time.Hour),
+//	)
+func AllowedWindow(start, end time.Duration) Option {
+	if start < 0 || end < 0 {
+		return func(c *Config) {
+			c.describe("AllowedWindow", start, end)
+			c.invalid("AllowedWindow must not be negative, got %v-%v", start, end)
+		}
+	}
+	start, end = start%day, end%day
+	if start == end {
+		return emptyOption
+	}
+	return func(c *Config) {
+		c.describe("AllowedWindow", start, end)
+		c.extend().windows = append(c.ext.windows, window{start: start, end: end})
+	}
+}
+
+// untilOpen returns how long to wait until the window is open, zero if it is open at `now`
+func (w window) untilOpen(now time.Time) time.Duration {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	sinceMidnight := now.Sub(midnight)
+
+	if w.start < w.end {
+		if sinceMidnight >= w.start && sinceMidnight < w.end {
+			return 0
+		}
+	} else if sinceMidnight >= w.start || sinceMidnight < w.end {
+		return 0
+	}
+
+	if sinceMidnight < w.start {
+		return w.start - sinceMidnight
+	}
+	return day - sinceMidnight + w.start
+}
+
+// waitForWindow waits until one of configured windows is open,
+// returns false when the context is done in the meantime
+func waitForWindow(config *Config) bool {
+	if len(config.ext.windows) == 0 {
+		return true
+	}
+
+	now := config.clock.Now()
+	wait := config.ext.windows[0].untilOpen(now)
+	for _, w := range config.ext.windows[1:] {
+		if d := w.untilOpen(now); d < wait {
+			wait = d
+		}
+	}
+	if wait == 0 {
+		return true
+	}
+
+	return config.sleep(wait)
+}
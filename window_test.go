@@ -0,0 +1,58 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowUntilOpen(t *testing.T) {
+	at := func(hour, minute int) time.Time {
+		return time.Date(2024, time.March, 6, hour, minute, 0, 0, time.UTC)
+	}
+
+	office := window{start: 9 * time.Hour, end: 17 * time.Hour}
+	assert.Equal(t, time.Duration(0), office.untilOpen(at(9, 0)))
+	assert.Equal(t, time.Duration(0), office.untilOpen(at(16, 59)))
+	assert.Equal(t, 90*time.Minute, office.untilOpen(at(7, 30)))
+	assert.Equal(t, 16*time.Hour, office.untilOpen(at(17, 0)))
+
+	night := window{start: 22 * time.Hour, end: 6 * time.Hour}
+	assert.Equal(t, time.Duration(0), night.untilOpen(at(23, 0)))
+	assert.Equal(t, time.Duration(0), night.untilOpen(at(5, 59)))
+	assert.Equal(t, 16*time.Hour, night.untilOpen(at(6, 0)))
+}
+
+func TestAllowedWindow(t *testing.T) {
+	now := time.Now()
+	sinceMidnight := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
+
+	timer := &recordingTimer{}
+	err := Do(
+		func() error { return errors.New("test") },
+		Attempts(1),
+		AllowedWindow(sinceMidnight+time.Hour, sinceMidnight+2*time.Hour),
+		AllowedWindow(sinceMidnight+3*time.Hour, sinceMidnight+4*time.Hour),
+		WithTimer(timer),
+	)
+	assert.Error(t, err)
+	assert.Len(t, timer.delays, 1, "first attempt deferred")
+	assert.InDelta(t, float64(time.Hour), float64(timer.delays[0]), float64(time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts := 0
+	err = Do(
+		func() error {
+			attempts++
+			return nil
+		},
+		AllowedWindow(sinceMidnight+time.Hour, sinceMidnight+2*time.Hour),
+		Context(ctx),
+	)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 0, attempts)
+}